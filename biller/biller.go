@@ -0,0 +1,170 @@
+package biller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+type AmountType string
+
+const (
+	// Fixed billers are paid exactly the amount due returned by the inquiry
+	Fixed AmountType = "FIXED"
+	// Open billers are paid any amount between MinAmount and MaxAmount, like prepaid top ups
+	Open AmountType = "OPEN"
+)
+
+var (
+	ErrBillNotFound = errors.New("no bill found for the customer reference")
+	ErrAlreadyPaid  = errors.New("bill is already paid")
+	ErrUnavailable  = errors.New("biller is not available")
+)
+
+// Biller is a company its customers can pay at the ATM. The customer reference identifies the customer
+// at the biller, like a meter or phone number, it should match ReferencePattern.
+type Biller struct {
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	ReferenceLabel   string     `json:"referenceLabel"`
+	ReferencePattern string     `json:"referencePattern"`
+	AmountType       AmountType `json:"amountType"`
+	MinAmount        float64    `json:"minAmount,omitempty"`
+	MaxAmount        float64    `json:"maxAmount,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// ValidReference reports whether reference has the format of the biller's customer references
+func (b Biller) ValidReference(reference string) bool {
+	if b.pattern == nil {
+		return reference != ""
+	}
+	return b.pattern.MatchString(reference)
+}
+
+// CheckAmount tells why amount cannot be paid to an OPEN biller
+func (b Biller) CheckAmount(amount float64) error {
+	if amount <= 0 {
+		return errors.New("invalid payment amount")
+	} else if b.MinAmount > 0 && amount < b.MinAmount {
+		return fmt.Errorf("minimum payment to %s is $%0.f", b.Name, b.MinAmount)
+	} else if b.MaxAmount > 0 && amount > b.MaxAmount {
+		return fmt.Errorf("maximum payment to %s is $%0.f", b.Name, b.MaxAmount)
+	}
+	return nil
+}
+
+// Bill is what the biller answers to an inquiry, AmountDue is 0 for OPEN billers
+type Bill struct {
+	BillerCode   string  `json:"billerCode"`
+	Reference    string  `json:"reference"`
+	CustomerName string  `json:"customerName"`
+	AmountDue    float64 `json:"amountDue,omitempty"`
+}
+
+// Client sends inquiries and payments to the billers. paymentID is the bank's reference of a payment,
+// Pay returns the reference the biller gave to it, printed on the receipt.
+type Client interface {
+	Inquire(ctx context.Context, b Biller, reference string) (*Bill, error)
+	Pay(ctx context.Context, b Biller, reference string, amount float64, paymentID string) (string, error)
+}
+
+// Registry is the list of billers customers can pay
+type Registry struct {
+	mu      sync.RWMutex
+	billers map[string]Biller
+}
+
+// Default are the billers of a registry loaded without a file
+func Default() []Biller {
+	return []Biller{
+		{Code: "ELEC", Name: "City Electricity", ReferenceLabel: "Meter Number", ReferencePattern: `^\d{11}$`,
+			AmountType: Open, MinAmount: 10, MaxAmount: 500},
+		{Code: "PHONE", Name: "Phone Company", ReferenceLabel: "Phone Number", ReferencePattern: `^0\d{9,11}$`,
+			AmountType: Fixed},
+		{Code: "WATER", Name: "City Water", ReferenceLabel: "Customer ID", ReferencePattern: `^\d{8}$`,
+			AmountType: Fixed},
+	}
+}
+
+func New(billers ...Biller) (*Registry, error) {
+	r := &Registry{}
+	if err := r.SetBillers(billers); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Load reads the billers from a JSON file, an empty path gives the Default billers
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return New(Default()...)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var billers []Biller
+	if err := json.Unmarshal(b, &billers); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling billers : %w", err)
+	}
+	return New(billers...)
+}
+
+// SetBillers replaces all the billers, biller codes should be unique
+func (r *Registry) SetBillers(billers []Biller) error {
+	byCode := make(map[string]Biller, len(billers))
+	for _, b := range billers {
+		if b.Code == "" || b.Name == "" {
+			return errors.New("biller code and name are required")
+		} else if _, ok := byCode[b.Code]; ok {
+			return fmt.Errorf("duplicate biller %s", b.Code)
+		} else if b.AmountType != Fixed && b.AmountType != Open {
+			return fmt.Errorf("amount type of biller %s should be FIXED or OPEN", b.Code)
+		} else if b.MaxAmount > 0 && b.MinAmount > b.MaxAmount {
+			return fmt.Errorf("minimum amount of biller %s is above its maximum amount", b.Code)
+		}
+		if b.ReferenceLabel == "" {
+			b.ReferenceLabel = "Customer Reference"
+		}
+		if b.ReferencePattern != "" {
+			pattern, err := regexp.Compile(b.ReferencePattern)
+			if err != nil {
+				return fmt.Errorf("invalid reference pattern of biller %s : %w", b.Code, err)
+			}
+			b.pattern = pattern
+		}
+		byCode[b.Code] = b
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.billers = byCode
+	return nil
+}
+
+func (r *Registry) Biller(code string) (Biller, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.billers[code]
+	return b, ok
+}
+
+// Billers lists the billers sorted by code
+func (r *Registry) Billers() []Biller {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	billers := make([]Biller, 0, len(r.billers))
+	for _, b := range r.billers {
+		billers = append(billers, b)
+	}
+	sort.Slice(billers, func(i, j int) bool {
+		return billers[i].Code < billers[j].Code
+	})
+	return billers
+}
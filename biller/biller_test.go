@@ -0,0 +1,77 @@
+package biller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBillers_Validation(t *testing.T) {
+	_, err := New(Biller{Name: "No code", AmountType: Fixed})
+	assert.NotNil(t, err)
+	_, err = New(Biller{Code: "A", Name: "A", AmountType: Fixed}, Biller{Code: "A", Name: "Again", AmountType: Fixed})
+	assert.NotNil(t, err)
+	_, err = New(Biller{Code: "A", Name: "A", AmountType: "SOMETIMES"})
+	assert.NotNil(t, err)
+	_, err = New(Biller{Code: "A", Name: "A", AmountType: Open, MinAmount: 100, MaxAmount: 10})
+	assert.NotNil(t, err)
+	_, err = New(Biller{Code: "A", Name: "A", AmountType: Fixed, ReferencePattern: "(("})
+	assert.NotNil(t, err)
+}
+
+func TestRegistry_DefaultBillers(t *testing.T) {
+	r, err := Load("")
+	assert.Nil(t, err)
+	assert.Len(t, r.Billers(), 3)
+	elec, ok := r.Biller("ELEC")
+	assert.True(t, ok)
+	assert.True(t, elec.ValidReference("12345678901"))
+	assert.False(t, elec.ValidReference("1234567890"))
+	assert.False(t, elec.ValidReference("1234567890a"))
+	assert.Nil(t, elec.CheckAmount(10))
+	assert.EqualError(t, elec.CheckAmount(5), "minimum payment to City Electricity is $10")
+	assert.EqualError(t, elec.CheckAmount(501), "maximum payment to City Electricity is $500")
+	_, ok = r.Biller("GAS")
+	assert.False(t, ok)
+}
+
+func TestLoad_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "billers.json")
+	os.WriteFile(path, []byte(`[{"code":"GAS","name":"Gas Company","referencePattern":"^G\\d{6}$","amountType":"FIXED"}]`), 0o644)
+	r, err := Load(path)
+	assert.Nil(t, err)
+	gas, ok := r.Biller("GAS")
+	assert.True(t, ok)
+	assert.True(t, gas.ValidReference("G123456"))
+	assert.False(t, gas.ValidReference("123456"))
+}
+
+func TestStub_InquireAndPay(t *testing.T) {
+	ctx := context.Background()
+	stub := NewStub()
+	phone := Biller{Code: "PHONE", Name: "Phone Company", AmountType: Fixed}
+
+	_, err := stub.Inquire(ctx, phone, "0812340000")
+	assert.Equal(t, ErrBillNotFound, err)
+	bill, err := stub.Inquire(ctx, phone, "0812345678")
+	assert.Nil(t, err)
+	assert.Equal(t, "Customer 5678", bill.CustomerName)
+	assert.True(t, bill.AmountDue >= 10 && bill.AmountDue < 100)
+	again, _ := stub.Inquire(ctx, phone, "0812345678")
+	assert.Equal(t, bill.AmountDue, again.AmountDue)
+
+	_, err = stub.Pay(ctx, phone, "0812345678", bill.AmountDue+1, "HLD00000001")
+	assert.NotNil(t, err)
+	ref, err := stub.Pay(ctx, phone, "0812345678", bill.AmountDue, "HLD00000001")
+	assert.Nil(t, err)
+	assert.Equal(t, "PHONE00000001", ref)
+	_, err = stub.Inquire(ctx, phone, "0812345678")
+	assert.Equal(t, ErrAlreadyPaid, err)
+
+	stub.SetDown(true)
+	_, err = stub.Inquire(ctx, phone, "0812349999")
+	assert.Equal(t, ErrUnavailable, err)
+}
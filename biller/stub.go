@@ -0,0 +1,77 @@
+package biller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Stub is an in-process biller service. Every reference has a bill, except the ones ending with 0000.
+// The amount due of a FIXED bill is derived from its reference, and a paid bill is not due anymore.
+type Stub struct {
+	mu       sync.Mutex
+	paid     map[string]bool
+	payments int
+	down     bool
+}
+
+func NewStub() *Stub {
+	return &Stub{paid: make(map[string]bool)}
+}
+
+// SetDown makes every inquiry and payment fail with ErrUnavailable until it is set back to false
+func (s *Stub) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *Stub) Inquire(ctx context.Context, b Biller, reference string) (*Bill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return nil, ErrUnavailable
+	}
+	return s.bill(b, reference)
+}
+
+func (s *Stub) Pay(ctx context.Context, b Biller, reference string, amount float64, paymentID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return "", ErrUnavailable
+	}
+	bill, err := s.bill(b, reference)
+	if err != nil {
+		return "", err
+	} else if b.AmountType == Fixed {
+		if amount != bill.AmountDue {
+			return "", fmt.Errorf("amount due is $%.2f", bill.AmountDue)
+		}
+		s.paid[b.Code+":"+reference] = true
+	}
+	s.payments++
+	return fmt.Sprintf("%s%08d", b.Code, s.payments), nil
+}
+
+func (s *Stub) bill(b Biller, reference string) (*Bill, error) {
+	if len(reference) < 4 || reference[len(reference)-4:] == "0000" {
+		return nil, ErrBillNotFound
+	}
+	bill := &Bill{
+		BillerCode:   b.Code,
+		Reference:    reference,
+		CustomerName: "Customer " + reference[len(reference)-4:],
+	}
+	if b.AmountType == Fixed {
+		if s.paid[b.Code+":"+reference] {
+			return nil, ErrAlreadyPaid
+		}
+		var due uint
+		for _, c := range reference {
+			due = due*31 + uint(c)
+		}
+		bill.AmountDue = float64(due%90 + 10)
+	}
+	return bill, nil
+}
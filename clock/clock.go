@@ -0,0 +1,92 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time, code that depends on time asks a Clock instead of calling time.Now
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Real is the clock of the machine
+func Real() Clock {
+	return realClock{}
+}
+
+// Fake is a clock that only moves when it is told to
+type Fake struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Adjustable is a clock that can be moved, like Fake and Offset
+type Adjustable interface {
+	Clock
+	Set(now time.Time)
+	Advance(d time.Duration)
+}
+
+// Offset is base moved by an offset, it keeps ticking like base after it is moved
+type Offset struct {
+	mu     sync.RWMutex
+	base   Clock
+	offset time.Duration
+}
+
+func NewOffset(base Clock) *Offset {
+	return &Offset{base: base}
+}
+
+func (o *Offset) Now() time.Time {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.base.Now().Add(o.offset)
+}
+
+func (o *Offset) Set(now time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.offset = now.Sub(o.base.Now())
+}
+
+func (o *Offset) Advance(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.offset += d
+}
+
+func (o *Offset) Offset() time.Duration {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.offset
+}
@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	assert.Equal(t, start, f.Now())
+	f.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), f.Now())
+	f.Set(start)
+	assert.Equal(t, start, f.Now())
+}
+
+func TestOffset(t *testing.T) {
+	base := NewFake(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	o := NewOffset(base)
+	assert.Equal(t, base.Now(), o.Now())
+
+	o.Set(time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC))
+	assert.Equal(t, 14*time.Hour+59*time.Minute, o.Offset())
+	base.Advance(2 * time.Minute)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 1, 0, 0, time.UTC), o.Now())
+	o.Advance(24 * time.Hour)
+	assert.Equal(t, time.Date(2026, 10, 21, 0, 1, 0, 0, time.UTC), o.Now())
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+type Client struct {
+	baseURL string
+	key     string
+	http    *http.Client
+}
+
+func NewClient(baseURL, key string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		key:     key,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+// do sends the request with the admin key and returns the response body of a successful request
+func (c *Client) do(method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("content-type", contentType)
+	}
+	req.Header.Set("X-Admin-Key", c.key)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, errors.New("the ATM server is not responding")
+		}
+		return nil, fmt.Errorf("cannot reach the ATM server : %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response : %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		rf := &responseFormatter.ResponseFormatter{}
+		if err := json.Unmarshal(b, rf); err != nil || rf.Message == "" {
+			return nil, errors.New(http.StatusText(resp.StatusCode))
+		}
+		return nil, errors.New(rf.Message)
+	}
+	return b, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+func importAccounts(args []string) error {
+	fs, sf := newFlagSet("import")
+	file := fs.String("file", "", "CSV file with Name,PIN,Balance,Account Number columns")
+	dryRun := fs.Bool("dry-run", false, "only validate the file, nothing is imported")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	path := "/api/v1/admin/accounts/import"
+	if *dryRun {
+		path += "?dryRun=true"
+	}
+	b, err := sf.client().do(http.MethodPost, path, "text/csv", f)
+	if err != nil {
+		return err
+	}
+	var report entity.ImportReport
+	if err := json.Unmarshal(b, &report); err != nil {
+		return fmt.Errorf("failed reading import report : %w", err)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("line %d: %s\n", e.Line, e.Message)
+	}
+	if report.DryRun {
+		fmt.Printf("Dry run: %d of %d accounts would be imported, %d rejected\n", report.Imported, report.Total, report.Rejected)
+	} else {
+		fmt.Printf("%d of %d accounts imported, %d rejected\n", report.Imported, report.Total, report.Rejected)
+	}
+	if report.Rejected > 0 {
+		return errors.New("some lines were rejected")
+	}
+	return nil
+}
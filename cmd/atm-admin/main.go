@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"import":    {usage: "import accounts from a Name,PIN,Balance,Account Number CSV file", run: importAccounts},
+	"statement": {usage: "export the statement of an account", run: statement},
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]].run == nil {
+		usage()
+		os.Exit(2)
+	}
+	if err := commands[os.Args[1]].run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: atm-admin <command> [flags]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "Run atm-admin <command> -h for the flags of a command")
+}
+
+type serverFlags struct {
+	url     string
+	key     string
+	timeout time.Duration
+}
+
+// newFlagSet creates the flags of a command with the flags every command needs to reach the server
+func newFlagSet(name string) (*flag.FlagSet, *serverFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	sf := &serverFlags{}
+	fs.StringVar(&sf.url, "url", "http://localhost:8080", "ATM simulation server address")
+	fs.StringVar(&sf.key, "key", os.Getenv("ADMIN_API_KEY"), "admin API key, defaults to ADMIN_API_KEY")
+	fs.DurationVar(&sf.timeout, "timeout", 30*time.Second, "timeout for each request to the server")
+	return fs, sf
+}
+
+func (sf *serverFlags) client() *Client {
+	return NewClient(sf.url, sf.key, sf.timeout)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+func statement(args []string) error {
+	fs, sf := newFlagSet("statement")
+	account := fs.String("account", "", "account number")
+	month := fs.String("month", "", "statement month, YYYY-MM")
+	from := fs.String("from", "", "first day of the statement, YYYY-MM-DD, when -month is not set")
+	to := fs.String("to", "", "last day of the statement, YYYY-MM-DD, when -month is not set")
+	format := fs.String("format", "pdf", "json, csv or pdf")
+	out := fs.String("o", "", "output file, defaults to statement-<account>-<period>.<format>")
+	fs.Parse(args)
+
+	if *account == "" {
+		return errors.New("-account is required")
+	}
+	q := url.Values{"format": {*format}}
+	period := *month
+	if *month != "" {
+		q.Set("month", *month)
+	} else {
+		q.Set("from", *from)
+		q.Set("to", *to)
+		period = *from + "_" + *to
+	}
+	b, err := sf.client().do(http.MethodGet,
+		fmt.Sprintf("/api/v1/admin/accounts/%s/statement?%s", url.PathEscape(*account), q.Encode()), "", nil)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = fmt.Sprintf("statement-%s-%s.%s", *account, period, *format)
+	}
+	if err := os.WriteFile(*out, b, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Statement written to", *out)
+	return nil
+}
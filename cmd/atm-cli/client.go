@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	atmscreen "github.com/fazarmitrais/atm-simulation/screen"
+)
+
+var errTimeout = errors.New("request timed out")
+
+// apiError is the ResponseFormatter returned by the backend when a request fails
+type apiError struct {
+	*responseFormatter.ResponseFormatter
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// otp is the one-time password sent with the next request only
+	otp string
+}
+
+func NewClient(baseURL string, timeout time.Duration) (*Client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Jar: jar, Timeout: timeout},
+	}, nil
+}
+
+// SetOTP sends otp with the next request, for an operation that needs a step-up
+func (c *Client) SetOTP(otp string) {
+	c.otp = otp
+}
+
+func (c *Client) Login(cardNumber, pin string) error {
+	return c.do(http.MethodPost, "/api/v1/account/validate", entity.CardLogin{
+		CardNumber: cardNumber,
+		PIN:        pin,
+	}, nil)
+}
+
+func (c *Client) RedeemCardless(code, pin string) (*entity.CardlessWithdrawal, error) {
+	var withdrawal entity.CardlessWithdrawal
+	err := c.do(http.MethodPost, "/api/v1/atm/cardless", entity.CardlessRedemption{Code: code, PIN: pin}, &withdrawal)
+	if err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+func (c *Client) FastCashPresets() ([]entity.FastCashPreset, error) {
+	var presets []entity.FastCashPreset
+	err := c.do(http.MethodGet, "/api/v1/account/withdraw/fast", nil, &presets)
+	if err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+func (c *Client) FastWithdraw(preset float64) (*entity.AccountResponse, error) {
+	var acc entity.AccountResponse
+	err := c.do(http.MethodPost, fmt.Sprintf("/api/v1/account/withdraw/fast/%v", preset), nil, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (c *Client) OtherWithdraw(amount float64) (*entity.AccountResponse, error) {
+	var acc entity.AccountResponse
+	err := c.do(http.MethodPost, "/api/v1/account/withdraw/other", map[string]float64{"amount": amount}, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (c *Client) Transfer(transfer entity.Transfer) (*entity.AccountResponse, error) {
+	var acc entity.AccountResponse
+	err := c.do(http.MethodPost, "/api/v1/account/transfer", transfer, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (c *Client) Beneficiaries() ([]entity.Beneficiary, error) {
+	var beneficiaries []entity.Beneficiary
+	err := c.do(http.MethodGet, "/api/v1/account/beneficiaries", nil, &beneficiaries)
+	if err != nil {
+		return nil, err
+	}
+	return beneficiaries, nil
+}
+
+func (c *Client) FeeQuote(operation string, amount float64) (*entity.FeeQuote, error) {
+	var quote entity.FeeQuote
+	err := c.do(http.MethodGet, fmt.Sprintf("/api/v1/account/fee?operation=%s&amount=%v", operation, amount), nil, &quote)
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (c *Client) FXQuote(toAccountNumber string, amount float64) (*entity.FXQuote, error) {
+	var quote entity.FXQuote
+	err := c.do(http.MethodGet, fmt.Sprintf("/api/v1/account/transfer/quote?toAccountNumber=%s&amount=%v", toAccountNumber, amount), nil, &quote)
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (c *Client) Balance() (*entity.AccountResponse, error) {
+	var acc entity.AccountResponse
+	err := c.do(http.MethodGet, "/api/v1/account/balance", nil, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (c *Client) Accounts() ([]entity.AccountResponse, error) {
+	var accounts []entity.AccountResponse
+	err := c.do(http.MethodGet, "/api/v1/account/accounts", nil, &accounts)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (c *Client) SelectAccount(accountNumber string) (*entity.AccountResponse, error) {
+	var acc entity.AccountResponse
+	err := c.do(http.MethodPost, "/api/v1/account/select", map[string]string{"accountNumber": accountNumber}, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (c *Client) Exit() error {
+	return c.do(http.MethodGet, "/api/v1/account/exit", nil, nil)
+}
+
+func (c *Client) Screen() (*atmscreen.State, error) {
+	var state atmscreen.State
+	err := c.do(http.MethodGet, "/api/v1/atm/screen", nil, &state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (c *Client) Navigate(event atmscreen.Event) error {
+	return c.do(http.MethodPost, "/api/v1/atm/screen", map[string]atmscreen.Event{"event": event}, nil)
+}
+
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	if c.otp != "" {
+		req.Header.Set("X-OTP", c.otp)
+		c.otp = ""
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return errTimeout
+		}
+		return fmt.Errorf("cannot reach the ATM server : %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed reading response : %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		rf := &responseFormatter.ResponseFormatter{}
+		if err := json.Unmarshal(b, rf); err != nil || rf.Message == "" {
+			return &apiError{responseFormatter.New(resp.StatusCode, http.StatusText(resp.StatusCode), true)}
+		}
+		return &apiError{rf}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("failed unmarshalling json : %w", err)
+	}
+	return nil
+}
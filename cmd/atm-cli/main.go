@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "ATM simulation server address")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout for each request to the server")
+	flag.Parse()
+
+	client, err := NewClient(strings.TrimRight(*baseURL, "/"), *timeout)
+	if err != nil {
+		log.Fatalln("Failed creating client :", err)
+	}
+	NewATM(client, os.Stdin, os.Stdout, clock.Real()).Run()
+}
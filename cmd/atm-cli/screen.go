@@ -0,0 +1,495 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	atmscreen "github.com/fazarmitrais/atm-simulation/screen"
+)
+
+const (
+	// error codes of the backend that ask for a one-time password
+	errCodeStepUpRequired = "STEP_UP_REQUIRED"
+	errCodeInvalidOTP     = "INVALID_OTP"
+	maxOTPTries           = 3
+)
+
+// screen renders itself, reads the user's choice and returns the next screen.
+// A nil screen stops the ATM.
+type screen func() screen
+
+type ATM struct {
+	client *Client
+	in     *bufio.Scanner
+	out    io.Writer
+	// clock gives the date printed on the summaries
+	clock clock.Clock
+	// beneficiaries are the saved beneficiaries of the account, listed on the transfer destination screen
+	beneficiaries []entity.Beneficiary
+	// currency is the currency of the selected account
+	currency string
+}
+
+func NewATM(client *Client, in io.Reader, out io.Writer, c clock.Clock) *ATM {
+	return &ATM{client: client, in: bufio.NewScanner(in), out: out, clock: c}
+}
+
+func (a *ATM) Run() {
+	for s := a.welcome; s != nil; {
+		s = s()
+	}
+}
+
+func (a *ATM) welcome() screen {
+	a.println()
+	a.println("Welcome to ATM Simulation")
+	cardNbr, ok := a.prompt("Enter Card Number (C for cardless withdrawal): ")
+	if !ok {
+		return nil
+	} else if strings.EqualFold(cardNbr, "C") {
+		return a.cardlessWithdrawal
+	}
+	pin, ok := a.prompt("Enter PIN: ")
+	if !ok {
+		return nil
+	}
+	if err := a.client.Login(cardNbr, pin); err != nil {
+		a.showError(err)
+		return a.welcome
+	}
+	return a.selectAccount
+}
+
+// cardlessWithdrawal dispenses a withdrawal staged from a phone, with its code and secondary PIN
+func (a *ATM) cardlessWithdrawal() screen {
+	a.println()
+	code, ok := a.prompt("Enter Withdrawal Code: ")
+	if !ok {
+		return nil
+	}
+	pin, ok := a.prompt("Enter Withdrawal PIN: ")
+	if !ok {
+		return nil
+	}
+	withdrawal, err := a.client.RedeemCardless(code, pin)
+	if err != nil {
+		a.showError(err)
+		return a.welcome
+	}
+	a.println()
+	a.println("Summary")
+	a.printf("Date : %s\n", a.clock.Now().Format("2006-01-02 03:04 PM"))
+	a.printf("Withdraw : %s\n", fx.FormatWhole(fx.Base, withdrawal.Amount))
+	if withdrawal.Dispensed < withdrawal.Amount {
+		a.printf("Dispensed : %s, the rest has been returned to the account\n", fx.FormatWhole(fx.Base, withdrawal.Dispensed))
+	}
+	a.println("Please take your cash")
+	return a.welcome
+}
+
+// selectAccount lets the customer pick the account to operate on when the card links more than one
+func (a *ATM) selectAccount() screen {
+	accounts, err := a.client.Accounts()
+	if err != nil {
+		return a.handleError(err)
+	}
+	if len(accounts) == 1 {
+		a.currency = accounts[0].Currency
+	}
+	if len(accounts) < 2 {
+		return a.transaction
+	}
+	a.println()
+	for i, acc := range accounts {
+		a.printf("%d. %s (%s)\n", i+1, acc.AccountNumber, strings.ToLower(string(acc.Type)))
+	}
+	option, ok := a.choose("Please choose account[1]: ", "1")
+	if !ok {
+		return nil
+	}
+	choice, err := strconv.Atoi(option)
+	if err != nil || choice < 1 || choice > len(accounts) {
+		return a.selectAccount
+	}
+	if _, err := a.client.SelectAccount(accounts[choice-1].AccountNumber); err != nil {
+		return a.handleError(err)
+	}
+	a.currency = accounts[choice-1].Currency
+	return a.transaction
+}
+
+func (a *ATM) transaction() screen {
+	a.println()
+	a.println("1. Withdraw")
+	a.println("2. Fund Transfer")
+	a.println("3. Exit")
+	option, ok := a.choose("Please choose option[3]: ", "3")
+	if !ok {
+		return nil
+	}
+	switch option {
+	case "1":
+		return a.navigate(atmscreen.EventWithdrawMenu, a.withdraw)
+	case "2":
+		return a.navigate(atmscreen.EventFundTransferMenu, a.transferDestination)
+	case "3":
+		return a.exit
+	}
+	return a.transaction
+}
+
+func (a *ATM) withdraw() screen {
+	presets, err := a.client.FastCashPresets()
+	if err != nil {
+		return a.handleError(err)
+	}
+	a.println()
+	for i, p := range presets {
+		if p.Available {
+			a.printf("%d. $%0.f\n", i+1, p.Amount)
+		} else {
+			a.printf("%d. $%0.f (not available)\n", i+1, p.Amount)
+		}
+	}
+	other, back := len(presets)+1, len(presets)+2
+	a.printf("%d. Other\n", other)
+	a.printf("%d. Back\n", back)
+	option, ok := a.choose(fmt.Sprintf("Please choose option[%d]: ", back), strconv.Itoa(back))
+	if !ok {
+		return nil
+	}
+	choice, err := strconv.Atoi(option)
+	switch {
+	case err != nil || choice < 1 || choice > back:
+		return a.withdraw
+	case choice == other:
+		return a.navigate(atmscreen.EventOtherAmount, a.otherWithdraw)
+	case choice == back:
+		return a.navigate(atmscreen.EventBack, a.transaction)
+	}
+	preset := presets[choice-1].Amount
+	var acc *entity.AccountResponse
+	err = a.withStepUp(func() (err error) {
+		acc, err = a.client.FastWithdraw(preset)
+		return err
+	})
+	if err != nil {
+		return a.handleError(err)
+	}
+	return a.withdrawSummary(preset, acc)
+}
+
+func (a *ATM) otherWithdraw() screen {
+	a.println()
+	a.println("Other Withdraw")
+	input, ok := a.prompt("Enter amount to withdraw: ")
+	if !ok {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		a.println("Invalid ammount")
+		return a.otherWithdraw
+	}
+	var acc *entity.AccountResponse
+	err = a.withStepUp(func() (err error) {
+		acc, err = a.client.OtherWithdraw(amount)
+		return err
+	})
+	if err != nil {
+		return a.handleError(err)
+	}
+	return a.withdrawSummary(amount, acc)
+}
+
+func (a *ATM) withdrawSummary(amount float64, acc *entity.AccountResponse) screen {
+	return func() screen {
+		a.println()
+		a.println("Summary")
+		a.printf("Date : %s\n", a.clock.Now().Format("2006-01-02 03:04 PM"))
+		a.printf("Withdraw : %s\n", fx.FormatWhole(acc.Currency, amount))
+		if acc.Dispensed > 0 && acc.Dispensed < amount {
+			a.printf("Dispensed : %s, the rest has been returned to your account\n", fx.FormatWhole(acc.Currency, acc.Dispensed))
+		}
+		if acc.Fee > 0 {
+			a.printf("Fee : %s\n", fx.Format(acc.Currency, acc.Fee))
+		}
+		a.printf("Balance : %s\n", fx.FormatWhole(acc.Currency, acc.Balance))
+		if acc.AvailableBalance != acc.Balance {
+			a.printf("Available : %s\n", fx.FormatWhole(acc.Currency, acc.AvailableBalance))
+		}
+		if acc.OverdraftLimit > 0 {
+			a.printf("Overdraft : %s of %s left\n", fx.FormatWhole(acc.Currency, acc.OverdraftHeadroom), fx.FormatWhole(acc.Currency, acc.OverdraftLimit))
+		}
+		a.println()
+		return a.summaryOptions()
+	}
+}
+
+func (a *ATM) transferDestination() screen {
+	a.println()
+	a.beneficiaries, _ = a.client.Beneficiaries()
+	if len(a.beneficiaries) > 0 {
+		a.println("Saved beneficiaries")
+		for i, b := range a.beneficiaries {
+			a.printf("%d. %s - %s (%s)\n", i+1, b.Nickname, b.ToAccountNumber, b.MaskedName)
+		}
+		a.println("Please enter destination account or beneficiary number and")
+	} else {
+		a.println("Please enter destination account and")
+	}
+	a.println("press enter to continue or")
+	dest, ok := a.prompt("press enter to go back to Transaction: ")
+	if !ok {
+		return nil
+	}
+	if dest == "" {
+		return a.navigate(atmscreen.EventBack, a.transaction)
+	}
+	if i, err := strconv.Atoi(dest); err == nil && i >= 1 && i <= len(a.beneficiaries) {
+		b := a.beneficiaries[i-1]
+		return a.transferAmount(entity.Transfer{ToAccountNumber: b.ToAccountNumber, BeneficiaryID: b.ID})
+	}
+	return a.transferAmount(entity.Transfer{ToAccountNumber: dest})
+}
+
+func (a *ATM) transferAmount(transfer entity.Transfer) screen {
+	return func() screen {
+		a.println()
+		a.println("Please enter transfer amount and press enter to continue or")
+		input, ok := a.prompt("press enter to go back to Transaction: ")
+		if !ok {
+			return nil
+		}
+		if input == "" {
+			return a.navigate(atmscreen.EventBack, a.transaction)
+		}
+		amount, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			a.println("Invalid amount")
+			return a.transferAmount(transfer)
+		}
+		transfer.Amount = amount
+		transfer.ReferenceNumber = fmt.Sprintf("%06d", rand.Intn(1000000))
+		return a.transferReference(transfer)
+	}
+}
+
+func (a *ATM) transferReference(transfer entity.Transfer) screen {
+	return func() screen {
+		a.println()
+		a.printf("Reference Number: %s\n", transfer.ReferenceNumber)
+		a.println("press enter to continue or")
+		input, ok := a.prompt("type 'back' to go back to Transaction: ")
+		if !ok {
+			return nil
+		}
+		if strings.EqualFold(input, "back") {
+			return a.navigate(atmscreen.EventBack, a.transaction)
+		}
+		return a.transferConfirmation(transfer)
+	}
+}
+
+func (a *ATM) transferConfirmation(transfer entity.Transfer) screen {
+	return func() screen {
+		a.println()
+		a.println("Transfer Confirmation")
+		a.printTransfer(transfer)
+		if quote, err := a.client.FXQuote(transfer.ToAccountNumber, transfer.Amount); err == nil && quote.FromCurrency != quote.ToCurrency {
+			a.printf("Exchange Rate       : 1 %s = %g %s\n", quote.FromCurrency, quote.Rate, quote.ToCurrency)
+			a.printf("Credited Amount     : %s\n", fx.Format(quote.ToCurrency, quote.ConvertedAmount))
+			a.printf("Rate Valid Until    : %s\n", quote.ExpiresAt.Format("15:04:05"))
+			transfer.QuoteID = quote.ID
+		}
+		if quote, err := a.client.FeeQuote("TRANSFER", transfer.Amount); err == nil && quote.Fee > 0 {
+			a.printf("Fee                 : %s\n", fx.Format(a.currency, quote.Fee))
+		}
+		a.println()
+		a.println("1. Confirm Trx")
+		a.println("2. Cancel Trx")
+		option, ok := a.choose("Choose option[2]: ", "2")
+		if !ok {
+			return nil
+		}
+		switch option {
+		case "1":
+			var acc *entity.AccountResponse
+			err := a.withStepUp(func() (err error) {
+				acc, err = a.client.Transfer(transfer)
+				return err
+			})
+			if err != nil {
+				return a.handleError(err)
+			}
+			return a.transferSummary(transfer, acc)
+		case "2":
+			return a.navigate(atmscreen.EventBack, a.transaction)
+		}
+		return a.transferConfirmation(transfer)
+	}
+}
+
+func (a *ATM) transferSummary(transfer entity.Transfer, acc *entity.AccountResponse) screen {
+	return func() screen {
+		a.println()
+		a.println("Fund Transfer Summary")
+		a.printTransfer(transfer)
+		if acc.ConvertedAmount > 0 {
+			a.printf("Credited Amount     : %s\n", fx.Format(acc.ConvertedCurrency, acc.ConvertedAmount))
+		}
+		if acc.Fee > 0 {
+			a.printf("Fee                 : %s\n", fx.Format(acc.Currency, acc.Fee))
+		}
+		a.printf("Balance             : %s\n", fx.FormatWhole(acc.Currency, acc.Balance))
+		if acc.AvailableBalance != acc.Balance {
+			a.printf("Available           : %s\n", fx.FormatWhole(acc.Currency, acc.AvailableBalance))
+		}
+		if acc.OverdraftLimit > 0 {
+			a.printf("Overdraft           : %s of %s left\n", fx.FormatWhole(acc.Currency, acc.OverdraftHeadroom), fx.FormatWhole(acc.Currency, acc.OverdraftLimit))
+		}
+		a.println()
+		return a.summaryOptions()
+	}
+}
+
+func (a *ATM) printTransfer(transfer entity.Transfer) {
+	a.printf("Destination Account : %s\n", transfer.ToAccountNumber)
+	for _, b := range a.beneficiaries {
+		if b.ID == transfer.BeneficiaryID {
+			a.printf("Beneficiary         : %s (%s)\n", b.Nickname, b.MaskedName)
+		}
+	}
+	a.printf("Transfer Amount     : %s\n", fx.FormatWhole(a.currency, transfer.Amount))
+	a.printf("Reference Number    : %s\n", transfer.ReferenceNumber)
+}
+
+func (a *ATM) summaryOptions() screen {
+	a.println("1. Transaction")
+	a.println("2. Exit")
+	option, ok := a.choose("Choose option[2]: ", "2")
+	if !ok {
+		return nil
+	}
+	switch option {
+	case "1":
+		return a.navigate(atmscreen.EventTransactionMenu, a.transaction)
+	case "2":
+		return a.exit
+	}
+	return a.summaryOptions
+}
+
+func (a *ATM) exit() screen {
+	if err := a.client.Exit(); err != nil {
+		a.showError(err)
+	}
+	return a.welcome
+}
+
+// navigate moves the server to the screen that follows event before showing next
+func (a *ATM) navigate(event atmscreen.Event, next screen) screen {
+	if err := a.client.Navigate(event); err != nil {
+		return a.handleError(err)
+	}
+	return next
+}
+
+// handleError shows the error and lands the customer on the screen the server says they are on:
+// an expired or missing session goes back to the Welcome screen.
+func (a *ATM) handleError(err error) screen {
+	a.showError(err)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		return a.welcome
+	}
+	return a.resync()
+}
+
+// withStepUp asks for a one-time password of the authenticator app and runs op again,
+// when the account has one and op is above the step-up threshold or to a new destination
+func (a *ATM) withStepUp(op func() error) error {
+	err := op()
+	for tries := 0; tries < maxOTPTries; tries++ {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || (apiErr.Code != errCodeStepUpRequired && apiErr.Code != errCodeInvalidOTP) {
+			return err
+		} else if apiErr.Code == errCodeInvalidOTP {
+			a.showError(err)
+		}
+		otp, ok := a.prompt("Enter one-time password: ")
+		if !ok {
+			return err
+		}
+		a.client.SetOTP(otp)
+		err = op()
+	}
+	return err
+}
+
+func (a *ATM) resync() screen {
+	state, err := a.client.Screen()
+	if err != nil {
+		a.showError(err)
+		return a.welcome
+	}
+	switch state.Screen {
+	case atmscreen.Transaction:
+		return a.transaction
+	case atmscreen.Withdraw:
+		return a.withdraw
+	case atmscreen.OtherWithdraw:
+		return a.otherWithdraw
+	case atmscreen.FundTransfer:
+		return a.transferDestination
+	case atmscreen.WithdrawSummary, atmscreen.FundTransferSummary:
+		return a.summaryOptions
+	}
+	return a.welcome
+}
+
+func (a *ATM) showError(err error) {
+	if errors.Is(err, errTimeout) {
+		a.println("The ATM server is not responding, please try again")
+		return
+	}
+	a.println(err.Error())
+}
+
+// prompt returns false when the input is closed
+func (a *ATM) prompt(label string) (string, bool) {
+	fmt.Fprint(a.out, label)
+	if !a.in.Scan() {
+		return "", false
+	}
+	return strings.TrimSpace(a.in.Text()), true
+}
+
+// choose returns def when the customer just presses enter
+func (a *ATM) choose(label, def string) (string, bool) {
+	input, ok := a.prompt(label)
+	if !ok {
+		return "", false
+	}
+	if input == "" {
+		return def, true
+	}
+	return input, true
+}
+
+func (a *ATM) println(args ...interface{}) {
+	fmt.Fprintln(a.out, args...)
+}
+
+func (a *ATM) printf(format string, args ...interface{}) {
+	fmt.Fprintf(a.out, format, args...)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	atmscreen "github.com/fazarmitrais/atm-simulation/screen"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackend serves the endpoints of one ATM session, it follows the screens with the real
+// screen machine so a request the server would reject on the current screen fails the same way
+type fakeBackend struct {
+	machine   *atmscreen.Machine
+	sessionID string
+	balance   float64
+	events    []atmscreen.Event
+}
+
+func newFakeBackend(t *testing.T, balance float64) (*fakeBackend, *Client) {
+	b := &fakeBackend{machine: atmscreen.New(), balance: balance}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/account/validate", b.login)
+	mux.HandleFunc("/api/v1/account/accounts", b.accounts)
+	mux.HandleFunc("/api/v1/account/withdraw/fast", b.presets)
+	mux.HandleFunc("/api/v1/account/withdraw/other", b.withdraw)
+	mux.HandleFunc("/api/v1/account/exit", b.exit)
+	mux.HandleFunc("/api/v1/atm/screen", b.navigate)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(srv.URL, time.Second)
+	assert.Nil(t, err)
+	return b, client
+}
+
+func (b *fakeBackend) login(w http.ResponseWriter, r *http.Request) {
+	var login entity.CardLogin
+	json.NewDecoder(r.Body).Decode(&login)
+	if login.CardNumber != "4000000000000010" || login.PIN != "012108" {
+		responseFormatter.New(http.StatusUnauthorized, "Invalid Card Number/PIN", true).ReturnAsJson(w)
+		return
+	}
+	b.sessionID = b.machine.Start()
+	writeTestJSON(w, responseFormatter.New(http.StatusOK, "Login success", false))
+}
+
+func (b *fakeBackend) accounts(w http.ResponseWriter, r *http.Request) {
+	writeTestJSON(w, []entity.AccountResponse{b.account()})
+}
+
+func (b *fakeBackend) presets(w http.ResponseWriter, r *http.Request) {
+	writeTestJSON(w, []entity.FastCashPreset{{Amount: 10, Available: true}, {Amount: 50, Available: true}})
+}
+
+func (b *fakeBackend) withdraw(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Amount float64 `json:"amount"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	if _, err := b.machine.Fire(b.sessionID, atmscreen.EventWithdraw); err != nil {
+		responseFormatter.New(http.StatusConflict, "Operation is not allowed on the current screen", true).ReturnAsJson(w)
+		return
+	}
+	b.balance -= req.Amount
+	writeTestJSON(w, b.account())
+}
+
+func (b *fakeBackend) exit(w http.ResponseWriter, r *http.Request) {
+	b.machine.End(b.sessionID)
+	writeTestJSON(w, responseFormatter.New(http.StatusOK, "Logout success", false))
+}
+
+func (b *fakeBackend) navigate(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		writeTestJSON(w, b.machine.Current(b.sessionID))
+		return
+	}
+	var nav struct {
+		Event atmscreen.Event `json:"event"`
+	}
+	json.NewDecoder(r.Body).Decode(&nav)
+	state, err := b.machine.Fire(b.sessionID, nav.Event)
+	if err != nil {
+		responseFormatter.New(http.StatusConflict, err.Error(), true).ReturnAsJson(w)
+		return
+	}
+	b.events = append(b.events, nav.Event)
+	writeTestJSON(w, state)
+}
+
+func (b *fakeBackend) account() entity.AccountResponse {
+	return entity.AccountResponse{AccountNumber: "112233", Balance: b.balance, AvailableBalance: b.balance, Currency: "USD"}
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func runATM(client *Client, input ...string) string {
+	var out bytes.Buffer
+	c := clock.NewFake(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local))
+	NewATM(client, strings.NewReader(strings.Join(input, "\n")+"\n"), &out, c).Run()
+	return out.String()
+}
+
+func TestATM_OtherWithdrawFlow(t *testing.T) {
+	backend, client := newFakeBackend(t, 100)
+	out := runATM(client, "4000000000000010", "012108", "1", "3", "30", "2")
+
+	assert.Equal(t, []atmscreen.Event{atmscreen.EventWithdrawMenu, atmscreen.EventOtherAmount}, backend.events)
+	assert.Contains(t, out, "1. $10\n2. $50\n3. Other\n4. Back\n")
+	assert.Contains(t, out, "Summary\nDate : 2026-10-19 09:00 AM\nWithdraw : $30\nBalance : $70\n")
+	assert.Equal(t, atmscreen.Welcome, backend.machine.Current(backend.sessionID).Screen)
+	assert.True(t, strings.HasSuffix(out, "Welcome to ATM Simulation\nEnter Card Number (C for cardless withdrawal): "))
+}
+
+func TestATM_InvalidLoginStaysOnWelcome(t *testing.T) {
+	backend, client := newFakeBackend(t, 100)
+	out := runATM(client, "4000000000000010", "111111")
+
+	assert.Contains(t, out, "Invalid Card Number/PIN\n")
+	assert.Equal(t, 2, strings.Count(out, "Welcome to ATM Simulation"))
+	assert.Empty(t, backend.events)
+}
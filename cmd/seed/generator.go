@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/repository"
+)
+
+var firstNames = []string{
+	"John", "Jane", "Michael", "Sarah", "David", "Maria", "James", "Linda", "Robert", "Patricia",
+	"Ahmad", "Siti", "Budi", "Dewi", "Kenji", "Yuki", "Carlos", "Ana", "Omar", "Fatima",
+}
+
+var lastNames = []string{
+	"Doe", "Smith", "Johnson", "Brown", "Garcia", "Miller", "Davis", "Wilson", "Anderson", "Taylor",
+	"Santoso", "Wijaya", "Pratama", "Tanaka", "Suzuki", "Silva", "Rossi", "Haddad", "Nguyen", "Kim",
+}
+
+type Options struct {
+	Accounts   int
+	MinBalance float64
+	MaxBalance float64
+	ATMID      string
+
+	// OpenedAt is the time of the opening balance journal entries, From is used instead when there is a history
+	OpenedAt time.Time
+
+	// History, when TransactionsPerAccount is not zero, simulates withdrawals and
+	// transfers between the generated accounts from From until To
+	TransactionsPerAccount int
+	From                   time.Time
+	To                     time.Time
+}
+
+// journal numbers the generated journal entries after the existing ones
+type journal struct {
+	seq     int
+	entries []entity.JournalEntry
+}
+
+func (j *journal) post(entry entity.JournalEntry, at time.Time, trxID string) {
+	j.seq++
+	entry.ID = entity.FormatJournalEntryID(j.seq)
+	entry.Time = at
+	entry.TransactionID = trxID
+	j.entries = append(j.entries, entry)
+}
+
+// Generate creates new accounts, and their history, that do not collide with existing.
+// Every balance change also gets its journal entry, starting with the opening balances.
+// The same seed and options always give the same data.
+func Generate(seed int64, opts Options, existing *repository.Snapshot) (*repository.Snapshot, error) {
+	if opts.Accounts < 1 {
+		return nil, errors.New("number of accounts should be at least 1")
+	} else if opts.MinBalance < 0 || opts.MaxBalance < opts.MinBalance {
+		return nil, errors.New("invalid balance range")
+	} else if opts.TransactionsPerAccount > 0 && !opts.To.After(opts.From) {
+		return nil, errors.New("history end should be after its start")
+	}
+	if existing == nil {
+		existing = &repository.Snapshot{}
+	}
+	used := make(map[string]bool, len(existing.Accounts))
+	for _, acc := range existing.Accounts {
+		used[acc.AccountNumber] = true
+	}
+	if opts.Accounts > 900000-len(used) {
+		return nil, fmt.Errorf("cannot generate %d unique 6 digits account numbers", opts.Accounts)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	generated := &repository.Snapshot{Accounts: make([]entity.Account, 0, opts.Accounts)}
+	for len(generated.Accounts) < opts.Accounts {
+		acctNbr := fmt.Sprintf("%06d", 100000+rnd.Intn(900000))
+		if used[acctNbr] {
+			continue
+		}
+		used[acctNbr] = true
+		generated.Accounts = append(generated.Accounts, entity.Account{
+			Name:          firstNames[rnd.Intn(len(firstNames))] + " " + lastNames[rnd.Intn(len(lastNames))],
+			AccountNumber: acctNbr,
+			PIN:           fmt.Sprintf("%06d", rnd.Intn(1000000)),
+			Balance:       math.Round(opts.MinBalance + rnd.Float64()*(opts.MaxBalance-opts.MinBalance)),
+			Status:        entity.AccountActive,
+		})
+	}
+	j := &journal{seq: len(existing.Journal)}
+	openedAt := opts.OpenedAt
+	if opts.TransactionsPerAccount > 0 {
+		openedAt = opts.From
+	}
+	for _, acc := range generated.Accounts {
+		if acc.Balance != 0 {
+			j.post(ledger.OpeningEntry(acc.AccountNumber, acc.Balance), openedAt, "")
+		}
+	}
+	if opts.TransactionsPerAccount > 0 {
+		generated.Transactions = history(rnd, opts, generated.Accounts, len(existing.Transactions), j)
+	}
+	generated.Journal = j.entries
+	return generated, nil
+}
+
+type event struct {
+	time    time.Time
+	account int
+}
+
+// history simulates the transactions of accounts in time order, updating their balances
+func history(rnd *rand.Rand, opts Options, accounts []entity.Account, seq int, j *journal) []entity.Transaction {
+	period := opts.To.Sub(opts.From)
+	events := make([]event, 0, len(accounts)*opts.TransactionsPerAccount)
+	for i := range accounts {
+		for j := 0; j < opts.TransactionsPerAccount; j++ {
+			at := opts.From.Add(time.Duration(rnd.Int63n(int64(period)))).Truncate(time.Second)
+			events = append(events, event{time: at, account: i})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.Before(events[j].time)
+	})
+
+	var trxs []entity.Transaction
+	record := func(trx entity.Transaction) {
+		seq++
+		trx.ID = entity.FormatTransactionID(seq)
+		trxs = append(trxs, trx)
+	}
+	for _, e := range events {
+		from := &accounts[e.account]
+		maxAmount := math.Min(1000, math.Floor(from.Balance))
+		if len(accounts) > 1 && rnd.Intn(2) == 0 {
+			if maxAmount < 1 {
+				continue
+			}
+			to := &accounts[(e.account+1+rnd.Intn(len(accounts)-1))%len(accounts)]
+			amount := float64(1 + rnd.Intn(int(maxAmount)))
+			ref := fmt.Sprintf("%06d", rnd.Intn(1000000))
+			from.Balance -= amount
+			to.Balance += amount
+			j.post(ledger.TransferEntry(from.AccountNumber, to.AccountNumber, amount), e.time, entity.FormatTransactionID(seq+1))
+			record(entity.Transaction{
+				ATMID: opts.ATMID, AccountNumber: from.AccountNumber, Type: entity.TransactionTransferOut,
+				Amount: amount, CounterpartAccountNumber: to.AccountNumber, ReferenceNumber: ref,
+				Balance: from.Balance, Time: e.time,
+			})
+			record(entity.Transaction{
+				ATMID: opts.ATMID, AccountNumber: to.AccountNumber, Type: entity.TransactionTransferIn,
+				Amount: amount, CounterpartAccountNumber: from.AccountNumber, ReferenceNumber: ref,
+				Balance: to.Balance, Time: e.time,
+			})
+			continue
+		}
+		if maxAmount < 10 {
+			continue
+		}
+		amount := float64(10 * (1 + rnd.Intn(int(maxAmount)/10)))
+		from.Balance -= amount
+		j.post(ledger.WithdrawEntry(from.AccountNumber, opts.ATMID, amount), e.time, entity.FormatTransactionID(seq+1))
+		record(entity.Transaction{
+			ATMID: opts.ATMID, AccountNumber: from.AccountNumber, Type: entity.TransactionWithdraw,
+			Amount: amount, Balance: from.Balance, Time: e.time,
+		})
+	}
+	return trxs
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var historyOpts = Options{
+	Accounts:               20,
+	MaxBalance:             2000,
+	ATMID:                  "ATM001",
+	TransactionsPerAccount: 10,
+	From:                   time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	To:                     time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a, err := Generate(42, historyOpts, nil)
+	assert.Nil(t, err)
+	b, _ := Generate(42, historyOpts, nil)
+	assert.Equal(t, a, b)
+
+	c, _ := Generate(43, historyOpts, nil)
+	assert.NotEqual(t, a.Accounts, c.Accounts)
+}
+
+func TestGenerate_UniqueAccountNumbers(t *testing.T) {
+	existing := &repository.Snapshot{Accounts: []entity.Account{{AccountNumber: "112233"}}}
+	generated, err := Generate(1, Options{Accounts: 500, MaxBalance: 100}, existing)
+	assert.Nil(t, err)
+	seen := map[string]bool{"112233": true}
+	for _, acc := range generated.Accounts {
+		assert.Len(t, acc.AccountNumber, 6)
+		assert.False(t, seen[acc.AccountNumber])
+		seen[acc.AccountNumber] = true
+	}
+}
+
+func TestGenerate_HistoryBalancesAddUp(t *testing.T) {
+	existing := &repository.Snapshot{Transactions: make([]entity.Transaction, 3)}
+	generated, err := Generate(7, historyOpts, existing)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, generated.Transactions)
+	assert.Equal(t, "TRX00000004", generated.Transactions[0].ID)
+
+	balances := make(map[string]float64)
+	for _, trx := range generated.Transactions {
+		if _, ok := balances[trx.AccountNumber]; !ok {
+			balances[trx.AccountNumber] = trx.Balance - trx.SignedAmount()
+		}
+		balances[trx.AccountNumber] += trx.SignedAmount()
+		assert.Equal(t, balances[trx.AccountNumber], trx.Balance)
+		assert.GreaterOrEqual(t, trx.Balance, float64(0))
+		assert.False(t, trx.Time.Before(historyOpts.From))
+		assert.True(t, trx.Time.Before(historyOpts.To))
+	}
+	for _, acc := range generated.Accounts {
+		if b, ok := balances[acc.AccountNumber]; ok {
+			assert.Equal(t, b, acc.Balance)
+		}
+	}
+}
+
+func TestGenerate_JournalMatchesBalances(t *testing.T) {
+	existing := &repository.Snapshot{Journal: make([]entity.JournalEntry, 2)}
+	generated, err := Generate(7, historyOpts, existing)
+	assert.Nil(t, err)
+	assert.Equal(t, "JE00000003", generated.Journal[0].ID)
+
+	l := ledger.New(clock.Real())
+	for _, e := range generated.Journal {
+		_, err := l.Post(e)
+		assert.Nil(t, err)
+	}
+	assert.Empty(t, l.Check())
+	for _, acc := range generated.Accounts {
+		assert.Equal(t, acc.Balance, l.CustomerBalance(acc.AccountNumber))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/lib/accountCsv"
+	"github.com/fazarmitrais/atm-simulation/repository"
+)
+
+func main() {
+	n := flag.Int("n", 100, "number of accounts to generate")
+	seed := flag.Int64("seed", 1, "random seed, the same seed gives the same data")
+	minBalance := flag.Float64("min-balance", 0, "minimum initial balance")
+	maxBalance := flag.Float64("max-balance", 5000, "maximum initial balance")
+	repoDSN := flag.String("repository", "", "repository to write to, memory or file:<path>")
+	csvPath := flag.String("csv", "", "CSV file to write the accounts to, in the import format")
+	trx := flag.Int("trx", 0, "number of simulated transactions per account, repository output only")
+	from := flag.String("from", "", "history start date, YYYY-MM-DD")
+	to := flag.String("to", "", "history end date, YYYY-MM-DD")
+	atm := flag.String("atm", "ATM001", "ATM ID of the simulated transactions")
+	flag.Parse()
+
+	opts := Options{
+		Accounts:               *n,
+		MinBalance:             *minBalance,
+		MaxBalance:             *maxBalance,
+		ATMID:                  *atm,
+		TransactionsPerAccount: *trx,
+		OpenedAt:               time.Now().Truncate(time.Second),
+	}
+	if err := run(*seed, opts, *repoDSN, *csvPath, *from, *to); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(seed int64, opts Options, repoDSN, csvPath, from, to string) error {
+	if (repoDSN == "") == (csvPath == "") {
+		return errors.New("either -repository or -csv is required")
+	}
+	if opts.TransactionsPerAccount > 0 {
+		if csvPath != "" {
+			return errors.New("the CSV import format has no transactions, use -repository for -trx")
+		}
+		var err error
+		if opts.From, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return errors.New("invalid -from date, use YYYY-MM-DD format")
+		}
+		if opts.To, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return errors.New("invalid -to date, use YYYY-MM-DD format")
+		}
+		opts.To = opts.To.AddDate(0, 0, 1)
+	}
+
+	if csvPath != "" {
+		generated, err := Generate(seed, opts, nil)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(csvPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := accountCsv.Write(f, generated.Accounts); err != nil {
+			return err
+		}
+		fmt.Printf("%d accounts written to %s\n", len(generated.Accounts), csvPath)
+		return nil
+	}
+
+	repo, err := repository.Open(repoDSN)
+	if err != nil {
+		return err
+	}
+	snapshot, err := repo.Load()
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		snapshot = &repository.Snapshot{}
+	}
+	generated, err := Generate(seed, opts, snapshot)
+	if err != nil {
+		return err
+	}
+	snapshot.Accounts = append(snapshot.Accounts, generated.Accounts...)
+	snapshot.Transactions = append(snapshot.Transactions, generated.Transactions...)
+	snapshot.Journal = append(snapshot.Journal, generated.Journal...)
+	if err := repo.Save(snapshot); err != nil {
+		return err
+	}
+	fmt.Printf("%d accounts and %d transactions written to %s\n",
+		len(generated.Accounts), len(generated.Transactions), repoDSN)
+	return nil
+}
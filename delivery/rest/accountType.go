@@ -0,0 +1,9 @@
+package rest
+
+import (
+	"net/http"
+)
+
+func (re *Rest) AccountTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.AccountTypes(r.Context()))
+}
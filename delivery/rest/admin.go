@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// readJSON unmarshals the request body into v, it writes the error response and returns false when it fails
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	b, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Add("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (re *Rest) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var acc entity.Account
+	if !readJSON(w, r, &acc) {
+		return
+	}
+	created, resp := re.service.CreateAccount(r.Context(), acc)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (re *Rest) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.ListAccounts(r.Context()))
+}
+
+func (re *Rest) GetAccount(w http.ResponseWriter, r *http.Request) {
+	acc, resp := re.service.GetAccount(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	type accountUpdate struct {
+		Name string `json:"name"`
+	}
+	var upd accountUpdate
+	if !readJSON(w, r, &upd) {
+		return
+	}
+	acc, resp := re.service.UpdateAccount(r.Context(), mux.Vars(r)["accountNumber"], upd.Name)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) ResetPIN(w http.ResponseWriter, r *http.Request) {
+	type pinReset struct {
+		PIN string `json:"pin"`
+	}
+	var reset pinReset
+	if !readJSON(w, r, &reset) {
+		return
+	}
+	if resp := re.service.ResetPIN(r.Context(), mux.Vars(r)["accountNumber"], reset.PIN); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	responseFormatter.New(http.StatusOK, "PIN has been reset", false).ReturnAsJson(w)
+}
+
+func (re *Rest) FreezeAccount(w http.ResponseWriter, r *http.Request) {
+	acc, resp := re.service.FreezeAccount(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) UnfreezeAccount(w http.ResponseWriter, r *http.Request) {
+	acc, resp := re.service.UnfreezeAccount(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	acc, resp := re.service.CloseAccount(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+// ImportAccounts reads a CSV file from the request body, ?dryRun=true only validates it
+func (re *Rest) ImportAccounts(w http.ResponseWriter, r *http.Request) {
+	report, resp := re.service.ImportAccounts(r.Context(), r.Body, r.URL.Query().Get("dryRun") == "true")
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) Beneficiaries(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.Beneficiaries(r.Context(), acctNbr))
+}
+
+func (re *Rest) AddBeneficiary(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	var beneficiary entity.Beneficiary
+	if !readJSON(w, r, &beneficiary) {
+		return
+	}
+	b, resp := re.service.AddBeneficiary(r.Context(), acctNbr, beneficiary)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, b)
+}
+
+func (re *Rest) RemoveBeneficiary(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	if resp := re.service.RemoveBeneficiary(r.Context(), acctNbr, mux.Vars(r)["id"]); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	responseFormatter.New(http.StatusOK, "Beneficiary has been removed", false).ReturnAsJson(w)
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+func (re *Rest) Billers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.Billers(r.Context()))
+}
+
+// BillInquiry shows the customer name and amount due of ?billerCode=ELEC&customerReference=12345678901
+func (re *Rest) BillInquiry(w http.ResponseWriter, r *http.Request) {
+	bill, resp := re.service.BillInquiry(r.Context(), r.URL.Query().Get("billerCode"), r.URL.Query().Get("customerReference"))
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, bill)
+}
+
+func (re *Rest) PayBill(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	var payment entity.BillPayment
+	if !readJSON(w, r, &payment) {
+		return
+	}
+	receipt, resp := re.service.PayBill(atmContext(r), acctNbr, payment)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	re.issueReceipt(r, receipt.TransactionID)
+	writeJSON(w, http.StatusOK, receipt)
+}
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// Accounts lists the accounts the card of the session can access
+func (re *Rest) Accounts(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	accounts, resp := re.service.Accounts(r.Context(), fmt.Sprintf("%v", cookieStore.Values["cardNumber"]))
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+// SelectAccount switches the account the next transactions of the session operate on
+func (re *Rest) SelectAccount(w http.ResponseWriter, r *http.Request) {
+	type selection struct {
+		AccountNumber string `json:"accountNumber"`
+	}
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	var sel selection
+	if !readJSON(w, r, &sel) {
+		return
+	}
+	acc, resp := re.service.SelectAccount(r.Context(), fmt.Sprintf("%v", cookieStore.Values["cardNumber"]), sel.AccountNumber)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	cookieStore.Values["acctNbr"] = acc.AccountNumber
+	cookieStore.Save(r, w)
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) IssueCard(w http.ResponseWriter, r *http.Request) {
+	var card entity.Card
+	if !readJSON(w, r, &card) {
+		return
+	}
+	c, resp := re.service.IssueCard(r.Context(), card)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, c)
+}
+
+func (re *Rest) GetCard(w http.ResponseWriter, r *http.Request) {
+	c, resp := re.service.GetCard(r.Context(), mux.Vars(r)["number"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (re *Rest) AccountCards(w http.ResponseWriter, r *http.Request) {
+	cards, resp := re.service.AccountCards(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, cards)
+}
+
+func (re *Rest) LinkCardAccounts(w http.ResponseWriter, r *http.Request) {
+	type link struct {
+		AccountNumbers []string `json:"accountNumbers"`
+	}
+	var req link
+	if !readJSON(w, r, &req) {
+		return
+	}
+	c, resp := re.service.LinkCardAccounts(r.Context(), mux.Vars(r)["number"], req.AccountNumbers)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (re *Rest) BlockCard(w http.ResponseWriter, r *http.Request) {
+	c, resp := re.service.BlockCard(r.Context(), mux.Vars(r)["number"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (re *Rest) UnblockCard(w http.ResponseWriter, r *http.Request) {
+	c, resp := re.service.UnblockCard(r.Context(), mux.Vars(r)["number"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) CreateCardlessWithdrawal(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	var req entity.CardlessWithdrawalRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	withdrawal, resp := re.service.CreateCardlessWithdrawal(otpContext(r), acctNbr, req)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, withdrawal)
+}
+
+func (re *Rest) CardlessWithdrawals(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.CardlessWithdrawals(r.Context(), acctNbr))
+}
+
+func (re *Rest) CancelCardlessWithdrawal(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	withdrawal, resp := re.service.CancelCardlessWithdrawal(r.Context(), acctNbr, mux.Vars(r)["id"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, withdrawal)
+}
+
+// RedeemCardlessWithdrawal dispenses a cardless withdrawal at the ATM, it needs no card and no session.
+// No receipt is printed, a receipt shows the balance of the account to whoever collects the cash.
+func (re *Rest) RedeemCardlessWithdrawal(w http.ResponseWriter, r *http.Request) {
+	var redemption entity.CardlessRedemption
+	if !readJSON(w, r, &redemption) {
+		return
+	}
+	withdrawal, resp := re.service.RedeemCardlessWithdrawal(atmContext(r), redemption)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, withdrawal)
+}
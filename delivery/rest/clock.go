@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+)
+
+func (re *Rest) Clock(w http.ResponseWriter, r *http.Request) {
+	type clockResponse struct {
+		Now        time.Time `json:"now"`
+		Adjustable bool      `json:"adjustable"`
+	}
+	c := re.service.Clock()
+	_, adjustable := c.(clock.Adjustable)
+	writeJSON(w, http.StatusOK, clockResponse{Now: c.Now(), Adjustable: adjustable})
+}
+
+// TravelTo moves the clock forward by {"advance":"2h"} or to {"time":"RFC3339"}, only in test mode,
+// and runs the batch jobs of the days that were skipped
+func (re *Rest) TravelTo(w http.ResponseWriter, r *http.Request) {
+	type travel struct {
+		Advance string     `json:"advance"`
+		Time    *time.Time `json:"time"`
+	}
+	type travelResponse struct {
+		Now  time.Time       `json:"now"`
+		Runs []scheduler.Run `json:"runs"`
+	}
+	if !testMode() {
+		responseFormatter.New(http.StatusForbidden, "Time travel is only available in test mode", true).ReturnAsJson(w)
+		return
+	}
+	var req travel
+	if !readJSON(w, r, &req) {
+		return
+	}
+	var to time.Time
+	switch {
+	case req.Time != nil && req.Advance != "":
+		responseFormatter.New(http.StatusBadRequest, "Use either advance or time, not both", true).ReturnAsJson(w)
+		return
+	case req.Time != nil:
+		to = *req.Time
+	case req.Advance != "":
+		d, err := time.ParseDuration(req.Advance)
+		if err != nil {
+			responseFormatter.New(http.StatusBadRequest, "Invalid advance, use a duration like 90m or 24h", true).ReturnAsJson(w)
+			return
+		}
+		to = re.service.Clock().Now().Add(d)
+	default:
+		responseFormatter.New(http.StatusBadRequest, "Either advance or time is required", true).ReturnAsJson(w)
+		return
+	}
+	runs, resp := re.service.TravelTo(r.Context(), to)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, travelResponse{Now: re.service.Clock().Now(), Runs: runs})
+}
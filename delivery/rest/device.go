@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/device"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// SetDispenserFault injects a fault into the dispenser of an ATM, only in test mode
+func (re *Rest) SetDispenserFault(w http.ResponseWriter, r *http.Request) {
+	type fault struct {
+		Fault string `json:"fault"`
+	}
+	if !testMode() {
+		responseFormatter.New(http.StatusForbidden, "Fault injection is only available in test mode", true).ReturnAsJson(w)
+		return
+	}
+	var req fault
+	if !readJSON(w, r, &req) {
+		return
+	}
+	f, err := device.ParseFault(req.Fault)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, err.Error(), true).ReturnAsJson(w)
+		return
+	}
+	if resp := re.service.SetDispenserFault(r.Context(), mux.Vars(r)["id"], f); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	responseFormatter.New(http.StatusOK, "Dispenser fault has been set", false).ReturnAsJson(w)
+}
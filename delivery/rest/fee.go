@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// FeeQuote tells the logged in customer the fee of ?operation=WITHDRAW|TRANSFER&amount=50 before confirming it,
+// &network=INTERBANK quotes it through the interbank network instead of on us
+func (re *Rest) FeeQuote(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	amount, err := strconv.ParseFloat(r.URL.Query().Get("amount"), 64)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, "Invalid amount", true).ReturnAsJson(w)
+		return
+	}
+	op := fee.Operation(strings.ToUpper(r.URL.Query().Get("operation")))
+	network := fee.OnUs
+	if n := r.URL.Query().Get("network"); n != "" {
+		network = fee.Network(strings.ToUpper(n))
+	}
+	quote, resp := re.service.FeeQuote(r.Context(), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), network, op, amount)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+func (re *Rest) FeeRules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.FeeRules(r.Context()))
+}
+
+// SetFeeRules replaces all the fee rules, they are kept until the app restarts
+func (re *Rest) SetFeeRules(w http.ResponseWriter, r *http.Request) {
+	var rules []fee.Rule
+	if !readJSON(w, r, &rules) {
+		return
+	}
+	if resp := re.service.SetFeeRules(r.Context(), rules); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.FeeRules(r.Context()))
+}
+
+func (re *Rest) SetAccountTier(w http.ResponseWriter, r *http.Request) {
+	type tierUpdate struct {
+		Tier string `json:"tier"`
+	}
+	var upd tierUpdate
+	if !readJSON(w, r, &upd) {
+		return
+	}
+	acc, resp := re.service.SetAccountTier(r.Context(), mux.Vars(r)["accountNumber"], upd.Tier)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
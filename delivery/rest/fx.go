@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// TransferQuote locks the exchange rate of a transfer of ?toAccountNumber=112244&amount=50 before confirming it
+func (re *Rest) TransferQuote(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	amount, err := strconv.ParseFloat(r.URL.Query().Get("amount"), 64)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, "Invalid amount", true).ReturnAsJson(w)
+		return
+	}
+	quote, resp := re.service.FXQuote(r.Context(), acctNbr, r.URL.Query().Get("toAccountNumber"), amount)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+func (re *Rest) FXRates(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.FXRates(r.Context()))
+}
+
+// SetFXRate sets the rate of a currency, it is kept until the app restarts
+func (re *Rest) SetFXRate(w http.ResponseWriter, r *http.Request) {
+	type rateUpdate struct {
+		Rate float64 `json:"rate"`
+	}
+	var upd rateUpdate
+	if !readJSON(w, r, &upd) {
+		return
+	}
+	rate, resp := re.service.SetFXRate(r.Context(), mux.Vars(r)["currency"], upd.Rate)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, rate)
+}
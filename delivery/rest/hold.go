@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/gorilla/mux"
+)
+
+// Holds lists the active holds of an account, all of them with ?all=true
+func (re *Rest) Holds(w http.ResponseWriter, r *http.Request) {
+	holds, resp := re.service.Holds(r.Context(), mux.Vars(r)["accountNumber"], r.URL.Query().Get("all") == "true")
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, holds)
+}
+
+func (re *Rest) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var hold entity.Hold
+	if !readJSON(w, r, &hold) {
+		return
+	}
+	h, resp := re.service.PlaceHold(r.Context(), mux.Vars(r)["accountNumber"], hold)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, h)
+}
+
+func (re *Rest) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	h, resp := re.service.ReleaseHold(r.Context(), mux.Vars(r)["id"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, h)
+}
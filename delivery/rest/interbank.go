@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/interbank"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) Banks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.Banks(r.Context()))
+}
+
+// TransferInquiry shows the masked name of the holder of ?bankCode=002&accountNumber=210001 before a transfer
+func (re *Rest) TransferInquiry(w http.ResponseWriter, r *http.Request) {
+	inquiry, resp := re.service.TransferInquiry(r.Context(), r.URL.Query().Get("bankCode"), r.URL.Query().Get("accountNumber"))
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, inquiry)
+}
+
+// SetBankFault makes a simulated bank decline or time out every transfer, only in test mode
+func (re *Rest) SetBankFault(w http.ResponseWriter, r *http.Request) {
+	type fault struct {
+		Fault string `json:"fault"`
+	}
+	if !testMode() {
+		responseFormatter.New(http.StatusForbidden, "Fault injection is only available in test mode", true).ReturnAsJson(w)
+		return
+	}
+	var req fault
+	if !readJSON(w, r, &req) {
+		return
+	}
+	f, err := interbank.ParseFault(req.Fault)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, err.Error(), true).ReturnAsJson(w)
+		return
+	}
+	if resp := re.service.SetBankFault(r.Context(), mux.Vars(r)["code"], f); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	responseFormatter.New(http.StatusOK, "Bank fault has been set", false).ReturnAsJson(w)
+}
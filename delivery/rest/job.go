@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/interest"
+)
+
+func (re *Rest) Jobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.Jobs(r.Context()))
+}
+
+// RunJobs runs the batch jobs that are due without waiting for the next check
+func (re *Rest) RunJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.RunJobs(r.Context()))
+}
+
+func (re *Rest) InterestRules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.InterestRules(r.Context()))
+}
+
+// SetInterestRules replaces all the interest rules, they are kept until the app restarts
+func (re *Rest) SetInterestRules(w http.ResponseWriter, r *http.Request) {
+	var rules []interest.Rule
+	if !readJSON(w, r, &rules) {
+		return
+	}
+	if resp := re.service.SetInterestRules(r.Context(), rules); resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.InterestRules(r.Context()))
+}
@@ -0,0 +1,14 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// LedgerCheck reports whether the ledger invariants hold, with the balance of every ledger account
+func (re *Rest) LedgerCheck(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.LedgerCheck(r.Context()))
+}
+
+func (re *Rest) Journal(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, re.service.Journal(r.Context()))
+}
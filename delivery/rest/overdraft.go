@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) SetOverdraft(w http.ResponseWriter, r *http.Request) {
+	type overdraft struct {
+		Limit float64 `json:"limit"`
+		Rate  float64 `json:"rate"`
+	}
+	var req overdraft
+	if !readJSON(w, r, &req) {
+		return
+	}
+	acc, resp := re.service.SetOverdraft(r.Context(), mux.Vars(r)["accountNumber"], req.Limit, req.Rate)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+// AccrueOverdraftInterest charges the overdraft interest of ?date=YYYY-MM-DD, today by default,
+// without waiting for the daily job
+func (re *Rest) AccrueOverdraftInterest(w http.ResponseWriter, r *http.Request) {
+	day := re.service.Clock().Now()
+	if date := r.URL.Query().Get("date"); date != "" {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			responseFormatter.New(http.StatusBadRequest, "Invalid date, use YYYY-MM-DD format", true).ReturnAsJson(w)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, re.service.AccrueOverdraftInterest(r.Context(), day))
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/receiptFormatter"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+// issueReceipt prints the receipt of a transaction unless the customer asked for no receipt with ?receipt=false.
+// The transaction is already done at this point, so a failure is only logged.
+func (re *Rest) issueReceipt(r *http.Request, trxID string) {
+	if r.URL.Query().Get("receipt") == "false" {
+		return
+	}
+	if _, resp := re.service.IssueReceipt(r.Context(), trxID); resp != nil {
+		log.Printf("Failed issuing receipt of transaction %s : %s \n", trxID, resp.Message)
+	}
+}
+
+func (re *Rest) Receipt(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	receipt, resp := re.service.Receipt(r.Context(), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), mux.Vars(r)["id"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Add("content-type", "application/json")
+		json.NewEncoder(w).Encode(receipt)
+	case "text":
+		w.Header().Add("content-type", "text/plain; charset=utf-8")
+		w.Write([]byte(receiptFormatter.Text(receipt)))
+	case "escpos":
+		w.Header().Add("content-type", "application/octet-stream")
+		w.Write(receiptFormatter.ESCPOS(receipt))
+	default:
+		responseFormatter.New(http.StatusBadRequest, "Format should be json, text or escpos", true).ReturnAsJson(w)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/fazarmitrais/atm-simulation/lib/envLib"
 	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
 	middleware "github.com/fazarmitrais/atm-simulation/middleware"
+	"github.com/fazarmitrais/atm-simulation/screen"
 	"github.com/fazarmitrais/atm-simulation/service"
 	"github.com/gorilla/mux"
 )
@@ -18,6 +19,7 @@ import (
 type Rest struct {
 	service *service.Service
 	cookie  *cookie.Cookie
+	screen  *screen.Machine
 }
 
 type ResponseFormatter struct {
@@ -27,16 +29,107 @@ type ResponseFormatter struct {
 
 func New(svc *service.Service) *Rest {
 	c := cookie.New()
-	return &Rest{service: svc, cookie: c}
+	return &Rest{service: svc, cookie: c, screen: screen.New()}
 }
 
 func (re *Rest) Register(m *mux.Router) {
-	m = m.PathPrefix("/api/v1/account").Subrouter()
-	m.HandleFunc("/validate", re.PINValidation).Methods(http.MethodPost)
-	m.HandleFunc("/withdraw", middleware.Chain(re.Withdraw, middleware.Required(re.cookie))).Methods(http.MethodPost)
-	m.HandleFunc("/transfer", middleware.Chain(re.Transfer, middleware.Required(re.cookie))).Methods(http.MethodPost)
-	m.HandleFunc("/balance", middleware.Chain(re.BalanceCheck, middleware.Required(re.cookie))).Methods(http.MethodGet)
-	m.HandleFunc("/exit", re.Exit).Methods(http.MethodGet)
+	a := m.PathPrefix("/api/v1/account").Subrouter()
+	a.HandleFunc("/validate", re.PINValidation).Methods(http.MethodPost)
+	a.HandleFunc("/withdraw", middleware.Chain(re.Withdraw,
+		middleware.Screen(re.cookie, re.screen, screen.EventWithdraw), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/withdraw/fast", middleware.Chain(re.FastCashPresets, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/withdraw/fast/{preset}", middleware.Chain(re.FastWithdraw,
+		middleware.Screen(re.cookie, re.screen, screen.EventFastCash), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/withdraw/other", middleware.Chain(re.OtherWithdraw,
+		middleware.Screen(re.cookie, re.screen, screen.EventWithdraw), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/transfer", middleware.Chain(re.Transfer,
+		middleware.Screen(re.cookie, re.screen, screen.EventTransfer), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/balance", middleware.Chain(re.BalanceCheck, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/receipt/{id}", middleware.Chain(re.Receipt, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/statement", middleware.Chain(re.Statement, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/fee", middleware.Chain(re.FeeQuote, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/dispute", middleware.Chain(re.Dispute, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/disputes", middleware.Chain(re.Disputes, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/accounts", middleware.Chain(re.Accounts, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/select", middleware.Chain(re.SelectAccount, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/billers", middleware.Chain(re.Billers, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/billpay/inquiry", middleware.Chain(re.BillInquiry, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/billpay", middleware.Chain(re.PayBill,
+		middleware.Screen(re.cookie, re.screen, screen.EventPayBill), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/banks", middleware.Chain(re.Banks, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/transfer/inquiry", middleware.Chain(re.TransferInquiry, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/transfer/quote", middleware.Chain(re.TransferQuote, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/beneficiaries", middleware.Chain(re.Beneficiaries, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/beneficiaries", middleware.Chain(re.AddBeneficiary, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/beneficiaries/{id}", middleware.Chain(re.RemoveBeneficiary, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodDelete)
+	a.HandleFunc("/standing-orders", middleware.Chain(re.CreateStandingOrder,
+		middleware.Screen(re.cookie, re.screen, screen.EventCreateStandingOrder), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/standing-orders", middleware.Chain(re.StandingOrders, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/standing-orders/{id}", middleware.Chain(re.CancelStandingOrder,
+		middleware.Screen(re.cookie, re.screen, screen.EventCancelStandingOrder), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodDelete)
+	a.HandleFunc("/notifications", middleware.Chain(re.Notifications, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/cardless", middleware.Chain(re.CreateCardlessWithdrawal,
+		middleware.Screen(re.cookie, re.screen, screen.EventCreateCardless), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/cardless", middleware.Chain(re.CardlessWithdrawals, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodGet)
+	a.HandleFunc("/cardless/{id}", middleware.Chain(re.CancelCardlessWithdrawal,
+		middleware.Screen(re.cookie, re.screen, screen.EventCancelCardless), middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodDelete)
+	a.HandleFunc("/totp", middleware.Chain(re.EnrolTOTP, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/totp/confirm", middleware.Chain(re.ConfirmTOTP, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	a.HandleFunc("/totp", middleware.Chain(re.DisableTOTP, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodDelete)
+	a.HandleFunc("/exit", re.Exit).Methods(http.MethodGet)
+
+	s := m.PathPrefix("/api/v1/atm").Subrouter()
+	s.HandleFunc("/screen", re.Screen).Methods(http.MethodGet)
+	s.HandleFunc("/screen", middleware.Chain(re.Navigate, middleware.Required(re.cookie, re.service.Clock()))).Methods(http.MethodPost)
+	s.HandleFunc("/cardless", re.RedeemCardlessWithdrawal).Methods(http.MethodPost)
+
+	ad := m.PathPrefix("/api/v1/admin").Subrouter()
+	ad.HandleFunc("/accounts", middleware.Chain(re.ListAccounts, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts", middleware.Chain(re.CreateAccount, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/import", middleware.Chain(re.ImportAccounts, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}", middleware.Chain(re.GetAccount, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts/{accountNumber}", middleware.Chain(re.UpdateAccount, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/accounts/{accountNumber}/pin", middleware.Chain(re.ResetPIN, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}/freeze", middleware.Chain(re.FreezeAccount, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}/unfreeze", middleware.Chain(re.UnfreezeAccount, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}/close", middleware.Chain(re.CloseAccount, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}/statement", middleware.Chain(re.AdminStatement, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts/{accountNumber}/transactions", middleware.Chain(re.AccountTransactions, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts/{accountNumber}/tier", middleware.Chain(re.SetAccountTier, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/accounts/{accountNumber}/cards", middleware.Chain(re.AccountCards, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/cards", middleware.Chain(re.IssueCard, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/cards/{number}", middleware.Chain(re.GetCard, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/cards/{number}/accounts", middleware.Chain(re.LinkCardAccounts, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/cards/{number}/block", middleware.Chain(re.BlockCard, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/cards/{number}/unblock", middleware.Chain(re.UnblockCard, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/accounts/{accountNumber}/standing-orders", middleware.Chain(re.AccountStandingOrders, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts/{accountNumber}/overdraft", middleware.Chain(re.SetOverdraft, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/accounts/{accountNumber}/totp", middleware.Chain(re.ResetTOTP, middleware.Admin())).Methods(http.MethodDelete)
+	ad.HandleFunc("/overdraft/interest", middleware.Chain(re.AccrueOverdraftInterest, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/interest", middleware.Chain(re.InterestRules, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/interest", middleware.Chain(re.SetInterestRules, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/jobs", middleware.Chain(re.Jobs, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/clock", middleware.Chain(re.Clock, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/clock", middleware.Chain(re.TravelTo, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/jobs/run", middleware.Chain(re.RunJobs, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/billers", middleware.Chain(re.Billers, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/account-types", middleware.Chain(re.AccountTypes, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/fees", middleware.Chain(re.FeeRules, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/fees", middleware.Chain(re.SetFeeRules, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/accounts/{accountNumber}/holds", middleware.Chain(re.Holds, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/accounts/{accountNumber}/holds", middleware.Chain(re.PlaceHold, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/holds/{id}", middleware.Chain(re.ReleaseHold, middleware.Admin())).Methods(http.MethodDelete)
+	ad.HandleFunc("/transactions/{id}", middleware.Chain(re.GetTransaction, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/transactions/{id}/reverse", middleware.Chain(re.Reverse, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/disputes", middleware.Chain(re.AdminDisputes, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/disputes/{id}/resolve", middleware.Chain(re.ResolveDispute, middleware.Admin())).Methods(http.MethodPost)
+	ad.HandleFunc("/atms/{id}/fault", middleware.Chain(re.SetDispenserFault, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/banks", middleware.Chain(re.Banks, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/banks/{code}/fault", middleware.Chain(re.SetBankFault, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/fx/rates", middleware.Chain(re.FXRates, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/fx/rates/{currency}", middleware.Chain(re.SetFXRate, middleware.Admin())).Methods(http.MethodPut)
+	ad.HandleFunc("/ledger", middleware.Chain(re.LedgerCheck, middleware.Admin())).Methods(http.MethodGet)
+	ad.HandleFunc("/ledger/journal", middleware.Chain(re.Journal, middleware.Admin())).Methods(http.MethodGet)
 }
 
 func (re *Rest) BalanceCheck(w http.ResponseWriter, r *http.Request) {
@@ -55,6 +148,9 @@ func (re *Rest) BalanceCheck(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// Exit logs out from any screen, like the cancel key of an ATM that returns the card in the middle of a
+// withdrawal, so it does not go through middleware.Screen : the EXIT options only tell the client where
+// to show it, and End brings the session back to the Welcome screen whatever screen it is on
 func (re *Rest) Exit(w http.ResponseWriter, r *http.Request) {
 	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
 	if err != nil {
@@ -63,8 +159,14 @@ func (re *Rest) Exit(w http.ResponseWriter, r *http.Request) {
 			ReturnAsJson(w)
 		return
 	}
+	if sessionID, ok := cookieStore.Values["sessionID"].(string); ok {
+		re.screen.End(sessionID)
+	}
 	cookieStore.Values["authenticated"] = false
 	cookieStore.Values["acctNbr"] = nil
+	cookieStore.Values["cardNumber"] = nil
+	cookieStore.Values["sessionID"] = nil
+	cookieStore.Values["lastSeen"] = nil
 	cookieStore.Save(r, w)
 	w.WriteHeader(http.StatusOK)
 	w.Header().Add("content-type", "application/json")
@@ -79,15 +181,15 @@ func (re *Rest) PINValidation(w http.ResponseWriter, r *http.Request) {
 			ReturnAsJson(w)
 		return
 	}
-	var acc entity.Account
-	err = json.Unmarshal(b, &acc)
+	var login entity.CardLogin
+	err = json.Unmarshal(b, &login)
 	if err != nil {
 		responseFormatter.New(http.StatusBadRequest,
 			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
 			ReturnAsJson(w)
 		return
 	}
-	errl := re.service.PINValidation(r.Context(), acc)
+	acc, errl := re.service.PINValidation(r.Context(), login)
 	if errl != nil {
 		errl.ReturnAsJson(w)
 		return
@@ -99,8 +201,14 @@ func (re *Rest) PINValidation(w http.ResponseWriter, r *http.Request) {
 			ReturnAsJson(w)
 		return
 	}
+	if sessionID, ok := cookieStore.Values["sessionID"].(string); ok {
+		re.screen.End(sessionID)
+	}
 	cookieStore.Values["authenticated"] = true
 	cookieStore.Values["acctNbr"] = acc.AccountNumber
+	cookieStore.Values["cardNumber"] = login.CardNumber
+	cookieStore.Values["sessionID"] = re.screen.Start()
+	cookieStore.Values["lastSeen"] = re.service.Clock().Now().Unix()
 	cookieStore.Save(r, w)
 	errl.ReturnAsJson(w)
 }
@@ -131,11 +239,12 @@ func (re *Rest) Withdraw(w http.ResponseWriter, r *http.Request) {
 			ReturnAsJson(w)
 		return
 	}
-	acc, resp := re.service.Withdraw(r.Context(), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), amt.Amount)
+	acc, resp := re.service.Withdraw(atmContext(r), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), amt.Amount)
 	if resp != nil {
 		resp.ReturnAsJson(w)
 		return
 	}
+	re.issueReceipt(r, acc.TransactionID)
 
 	w.WriteHeader(http.StatusOK)
 	w.Header().Add("content-type", "application/json")
@@ -166,13 +275,26 @@ func (re *Rest) Transfer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	transfer.FromAccountNumber = cookieStore.Values["acctNbr"].(string)
-	acc, resp := re.service.Transfer(r.Context(), transfer)
+	acc, resp := re.service.Transfer(atmContext(r), transfer)
 	if resp != nil {
 		resp.ReturnAsJson(w)
 		return
 	}
+	re.issueReceipt(r, acc.TransactionID)
 
 	w.WriteHeader(http.StatusOK)
 	w.Header().Add("content-type", "application/json")
 	json.NewEncoder(w).Encode(acc)
 }
+
+// sessionAccount is the account the session operates on, it writes the error response when there is none
+func (re *Rest) sessionAccount(w http.ResponseWriter, r *http.Request) (string, bool) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return "", false
+	}
+	return fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), true
+}
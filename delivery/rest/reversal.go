@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) Dispute(w http.ResponseWriter, r *http.Request) {
+	type dispute struct {
+		TransactionID string `json:"transactionId"`
+		Reason        string `json:"reason"`
+	}
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	var req dispute
+	if !readJSON(w, r, &req) {
+		return
+	}
+	d, resp := re.service.Dispute(r.Context(), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), req.TransactionID, req.Reason)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, d)
+}
+
+func (re *Rest) Disputes(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.Disputes(r.Context(), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), ""))
+}
+
+// AdminDisputes lists the disputes of every account, filtered with ?status=OPEN|REVERSED|REJECTED
+func (re *Rest) AdminDisputes(w http.ResponseWriter, r *http.Request) {
+	status := entity.DisputeStatus(r.URL.Query().Get("status"))
+	writeJSON(w, http.StatusOK, re.service.Disputes(r.Context(), "", status))
+}
+
+func (re *Rest) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	type resolution struct {
+		Action     string  `json:"action"`
+		Amount     float64 `json:"amount"`
+		Resolution string  `json:"resolution"`
+	}
+	var req resolution
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Action != "REVERSE" && req.Action != "REJECT" {
+		responseFormatter.New(http.StatusBadRequest, "Action should be REVERSE or REJECT", true).ReturnAsJson(w)
+		return
+	}
+	d, resp := re.service.ResolveDispute(r.Context(), mux.Vars(r)["id"], req.Action == "REVERSE", req.Amount, req.Resolution)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// Reverse reverses a transaction, all of it unless the body has an amount
+func (re *Rest) Reverse(w http.ResponseWriter, r *http.Request) {
+	type reversal struct {
+		Amount float64 `json:"amount"`
+		Reason string  `json:"reason"`
+	}
+	var req reversal
+	if r.ContentLength != 0 && !readJSON(w, r, &req) {
+		return
+	}
+	trx, resp := re.service.Reverse(r.Context(), mux.Vars(r)["id"], req.Amount, req.Reason)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, trx)
+}
+
+func (re *Rest) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	trx, resp := re.service.GetTransaction(r.Context(), mux.Vars(r)["id"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, trx)
+}
+
+func (re *Rest) AccountTransactions(w http.ResponseWriter, r *http.Request) {
+	trxs, resp := re.service.Transactions(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, trxs)
+}
@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/screen"
+)
+
+func (re *Rest) Screen(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	sessionID, _ := cookieStore.Values["sessionID"].(string)
+	state, resp := re.withFastCash(atmContext(r), re.screen.Current(sessionID))
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	w.Header().Add("content-type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+func (re *Rest) Navigate(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	type navigation struct {
+		Event screen.Event `json:"event"`
+	}
+	nav := navigation{}
+	err = json.Unmarshal(b, &nav)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	if screen.IsOperation(nav.Event) {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("%s can only be done through its own endpoint", nav.Event), true).
+			ReturnAsJson(w)
+		return
+	}
+	sessionID, _ := cookieStore.Values["sessionID"].(string)
+	state, err := re.screen.Fire(sessionID, nav.Event)
+	if err != nil {
+		responseFormatter.New(http.StatusConflict, "Operation is not allowed on the current screen", true).
+			ReturnAsJson(w)
+		return
+	}
+	state, resp := re.withFastCash(atmContext(r), state)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	w.Header().Add("content-type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// withFastCash lists the fast cash presets of the ATM on the withdraw screen
+func (re *Rest) withFastCash(ctx context.Context, state screen.State) (screen.State, *responseFormatter.ResponseFormatter) {
+	if state.Screen != screen.Withdraw {
+		return state, nil
+	}
+	presets, resp := re.service.FastCashPresets(ctx)
+	if resp != nil {
+		return state, resp
+	}
+	return state.WithFastCash(presets), nil
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/screen"
+	"github.com/fazarmitrais/atm-simulation/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoggedInClient serves the REST API of a new service and logs John in
+func newLoggedInClient(t *testing.T) (*http.Client, string) {
+	t.Setenv("COOKIE_STORE_NAME", "atm-test")
+	t.Setenv("COOKIE_SECRET_KEY", "atm-test-secret-key")
+	m := mux.NewRouter()
+	New(service.New()).Register(m)
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	resp := post(t, client, srv.URL+"/api/v1/account/validate", entity.CardLogin{CardNumber: "4000000000000010", PIN: "012108"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	return client, srv.URL
+}
+
+func post(t *testing.T, client *http.Client, url string, body interface{}) *http.Response {
+	b, _ := json.Marshal(body)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(b))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func TestScreen_OperationsOnlyFromTheirScreen(t *testing.T) {
+	client, url := newLoggedInClient(t)
+	order := entity.StandingOrder{ToAccountNumber: "112244", Amount: 30, Day: 20}
+	cardless := entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321"}
+
+	assert.Equal(t, http.StatusConflict, post(t, client, url+"/api/v1/account/standing-orders", order).StatusCode)
+	assert.Equal(t, http.StatusConflict, post(t, client, url+"/api/v1/account/cardless", cardless).StatusCode)
+	assert.Equal(t, http.StatusConflict, post(t, client, url+"/api/v1/account/billpay", entity.BillPayment{}).StatusCode)
+
+	resp := post(t, client, url+"/api/v1/atm/screen", map[string]screen.Event{"event": screen.EventStandingOrderMenu})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, post(t, client, url+"/api/v1/account/standing-orders", order).StatusCode)
+	assert.Equal(t, http.StatusConflict, post(t, client, url+"/api/v1/account/cardless", cardless).StatusCode)
+}
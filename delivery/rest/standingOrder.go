@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/gorilla/mux"
+)
+
+// CreateStandingOrder sets up a recurring transfer from the account of the session
+func (re *Rest) CreateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	var order entity.StandingOrder
+	if !readJSON(w, r, &order) {
+		return
+	}
+	o, resp := re.service.CreateStandingOrder(otpContext(r), acctNbr, order)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, o)
+}
+
+// StandingOrders lists the active standing orders of the session's account, all of them with ?all=true
+func (re *Rest) StandingOrders(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	orders, resp := re.service.StandingOrders(r.Context(), acctNbr, r.URL.Query().Get("all") == "true")
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (re *Rest) CancelStandingOrder(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	o, resp := re.service.CancelStandingOrder(r.Context(), acctNbr, mux.Vars(r)["id"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (re *Rest) Notifications(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, re.service.Notifications(r.Context(), acctNbr))
+}
+
+// AccountStandingOrders lists the active standing orders of an account, all of them with ?all=true
+func (re *Rest) AccountStandingOrders(w http.ResponseWriter, r *http.Request) {
+	orders, resp := re.service.StandingOrders(r.Context(), mux.Vars(r)["accountNumber"], r.URL.Query().Get("all") == "true")
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, orders)
+}
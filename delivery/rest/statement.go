@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/lib/statementFormatter"
+	"github.com/gorilla/mux"
+)
+
+func (re *Rest) Statement(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	re.writeStatement(w, r, fmt.Sprintf("%v", cookieStore.Values["acctNbr"]))
+}
+
+func (re *Rest) AdminStatement(w http.ResponseWriter, r *http.Request) {
+	re.writeStatement(w, r, mux.Vars(r)["accountNumber"])
+}
+
+// writeStatement takes the period either as ?month=2006-01 or as ?from=2006-01-02&to=2006-01-02
+// and the format as ?format=json|csv|pdf
+func (re *Rest) writeStatement(w http.ResponseWriter, r *http.Request, acctNbr string) {
+	from, to, err := statementPeriod(r)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, err.Error(), true).ReturnAsJson(w)
+		return
+	}
+	st, resp := re.service.Statement(r.Context(), acctNbr, from, to)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	fileName := fmt.Sprintf("statement-%s-%s", acctNbr, from.Format("20060102"))
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Add("content-type", "application/json")
+		json.NewEncoder(w).Encode(st)
+	case "csv":
+		b, err := statementFormatter.CSV(st)
+		if err != nil {
+			responseFormatter.New(http.StatusInternalServerError,
+				fmt.Sprintf("Failed writing csv : %s", err.Error()), true).
+				ReturnAsJson(w)
+			return
+		}
+		w.Header().Add("content-type", "text/csv")
+		w.Header().Add("content-disposition", fmt.Sprintf("attachment; filename=%s.csv", fileName))
+		w.Write(b)
+	case "pdf":
+		w.Header().Add("content-type", "application/pdf")
+		w.Header().Add("content-disposition", fmt.Sprintf("attachment; filename=%s.pdf", fileName))
+		w.Write(statementFormatter.PDF(st))
+	default:
+		responseFormatter.New(http.StatusBadRequest, "Format should be json, csv or pdf", true).ReturnAsJson(w)
+	}
+}
+
+func statementPeriod(r *http.Request) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+	if month := q.Get("month"); month != "" {
+		from, err := time.ParseInLocation("2006-01", month, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid month, use YYYY-MM format")
+		}
+		return from, from.AddDate(0, 1, -1), nil
+	}
+	from, err := time.ParseInLocation("2006-01-02", q.Get("from"), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid from date, use YYYY-MM-DD format")
+	}
+	to, err := time.ParseInLocation("2006-01-02", q.Get("to"), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid to date, use YYYY-MM-DD format")
+	}
+	return from, to, nil
+}
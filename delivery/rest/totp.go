@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/service"
+	"github.com/gorilla/mux"
+)
+
+// otpContext passes the one-time password of the X-OTP header to the operations that need a step-up
+func otpContext(r *http.Request) context.Context {
+	return service.WithOTP(r.Context(), r.Header.Get("X-OTP"))
+}
+
+func (re *Rest) EnrolTOTP(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	enrolment, resp := re.service.EnrolTOTP(r.Context(), acctNbr)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusCreated, enrolment)
+}
+
+// ConfirmTOTP enables the second factor with a first code of the authenticator app, {"otp": "123456"}
+func (re *Rest) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	type confirmation struct {
+		OTP string `json:"otp"`
+	}
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	var req confirmation
+	if !readJSON(w, r, &req) {
+		return
+	}
+	acc, resp := re.service.ConfirmTOTP(r.Context(), acctNbr, req.OTP)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	acctNbr, ok := re.sessionAccount(w, r)
+	if !ok {
+		return
+	}
+	acc, resp := re.service.DisableTOTP(otpContext(r), acctNbr)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (re *Rest) ResetTOTP(w http.ResponseWriter, r *http.Request) {
+	acc, resp := re.service.ResetTOTP(r.Context(), mux.Vars(r)["accountNumber"])
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/fazarmitrais/atm-simulation/device"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/service"
+	"github.com/gorilla/mux"
+)
+
+// atmContext passes the ATM sending the request, identified by the X-ATM-ID header, and the one-time password
+// of the X-OTP header to the service. In test mode the X-Simulate-Fault header also injects a dispenser fault into the request.
+func atmContext(r *http.Request) context.Context {
+	ctx := service.WithATM(otpContext(r), r.Header.Get("X-ATM-ID"))
+	if testMode() {
+		if f, err := device.ParseFault(r.Header.Get("X-Simulate-Fault")); err == nil {
+			ctx = device.WithFault(ctx, f)
+		}
+	}
+	return ctx
+}
+
+func testMode() bool {
+	return envLib.GetEnv("ATM_TEST_MODE") == "true"
+}
+
+func (re *Rest) FastCashPresets(w http.ResponseWriter, r *http.Request) {
+	presets, resp := re.service.FastCashPresets(atmContext(r))
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	w.Header().Add("content-type", "application/json")
+	json.NewEncoder(w).Encode(presets)
+}
+
+func (re *Rest) FastWithdraw(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	preset, err := strconv.ParseFloat(mux.Vars(r)["preset"], 64)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest, "Invalid fast cash option", true).ReturnAsJson(w)
+		return
+	}
+	acc, resp := re.service.FastWithdraw(atmContext(r), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), preset)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	re.issueReceipt(r, acc.TransactionID)
+	w.Header().Add("content-type", "application/json")
+	json.NewEncoder(w).Encode(acc)
+}
+
+func (re *Rest) OtherWithdraw(w http.ResponseWriter, r *http.Request) {
+	cookieStore, err := re.cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+	if err != nil {
+		responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Error getting cookie store : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	type withdrawAmount struct {
+		Amount float64 `json:"amount"`
+	}
+	amt := withdrawAmount{}
+	err = json.Unmarshal(b, &amt)
+	if err != nil {
+		responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Failed unmarshalling json : %s", err.Error()), true).
+			ReturnAsJson(w)
+		return
+	}
+	acc, resp := re.service.OtherWithdraw(atmContext(r), fmt.Sprintf("%v", cookieStore.Values["acctNbr"]), amt.Amount)
+	if resp != nil {
+		resp.ReturnAsJson(w)
+		return
+	}
+	re.issueReceipt(r, acc.TransactionID)
+	w.Header().Add("content-type", "application/json")
+	json.NewEncoder(w).Encode(acc)
+}
@@ -0,0 +1,98 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Dispenser hands out cash, it returns how much was actually dispensed even when it fails
+type Dispenser interface {
+	Dispense(ctx context.Context, atmID string, amount float64) (float64, error)
+}
+
+type Fault string
+
+const (
+	FaultNone    Fault = ""
+	FaultJam     Fault = "JAM"
+	FaultPartial Fault = "PARTIAL"
+	FaultTimeout Fault = "TIMEOUT"
+)
+
+// noteValue is the value of the smallest note in the dispenser
+const noteValue = 10
+
+var (
+	ErrJam          = errors.New("dispenser jammed")
+	ErrPartial      = errors.New("dispenser could only dispense part of the amount")
+	ErrTimeout      = errors.New("dispenser did not answer in time")
+	ErrUnknownFault = errors.New("unknown fault, use JAM, PARTIAL, TIMEOUT or NONE")
+)
+
+// ParseFault reads a fault name, NONE or an empty name clears the fault
+func ParseFault(name string) (Fault, error) {
+	switch f := Fault(strings.ToUpper(strings.TrimSpace(name))); f {
+	case FaultNone, FaultJam, FaultPartial, FaultTimeout:
+		return f, nil
+	case "NONE":
+		return FaultNone, nil
+	}
+	return FaultNone, ErrUnknownFault
+}
+
+type faultContextKey struct{}
+
+// WithFault makes the next dispense of the request fail with f, whatever the fault of the ATM is
+func WithFault(ctx context.Context, f Fault) context.Context {
+	return context.WithValue(ctx, faultContextKey{}, f)
+}
+
+// Simulated is a dispenser that always works unless a fault is injected, per ATM or per request
+type Simulated struct {
+	mu     sync.Mutex
+	faults map[string]Fault
+}
+
+func NewSimulated() *Simulated {
+	return &Simulated{faults: make(map[string]Fault)}
+}
+
+// SetFault makes every dispense of the ATM fail with f until it is set back to FaultNone
+func (d *Simulated) SetFault(atmID string, f Fault) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if f == FaultNone {
+		delete(d.faults, atmID)
+		return
+	}
+	d.faults[atmID] = f
+}
+
+func (d *Simulated) Fault(atmID string) Fault {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.faults[atmID]
+}
+
+func (d *Simulated) Dispense(ctx context.Context, atmID string, amount float64) (float64, error) {
+	f, ok := ctx.Value(faultContextKey{}).(Fault)
+	if !ok || f == FaultNone {
+		f = d.Fault(atmID)
+	}
+	switch f {
+	case FaultJam:
+		return 0, ErrJam
+	case FaultTimeout:
+		return 0, ErrTimeout
+	case FaultPartial:
+		// half of the notes come out
+		return math.Floor(amount/noteValue/2) * noteValue, ErrPartial
+	case FaultNone:
+		return amount, nil
+	}
+	return 0, fmt.Errorf("%w : %s", ErrUnknownFault, f)
+}
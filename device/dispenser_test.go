@@ -0,0 +1,45 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulated_Faults(t *testing.T) {
+	d := NewSimulated()
+	ctx := context.Background()
+
+	dispensed, err := d.Dispense(ctx, "ATM001", 90)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(90), dispensed)
+
+	d.SetFault("ATM001", FaultPartial)
+	dispensed, err = d.Dispense(ctx, "ATM001", 90)
+	assert.Equal(t, ErrPartial, err)
+	assert.Equal(t, float64(40), dispensed)
+
+	dispensed, err = d.Dispense(ctx, "ATM002", 90)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(90), dispensed)
+
+	dispensed, err = d.Dispense(WithFault(ctx, FaultJam), "ATM001", 90)
+	assert.Equal(t, ErrJam, err)
+	assert.Equal(t, float64(0), dispensed)
+
+	d.SetFault("ATM001", FaultNone)
+	_, err = d.Dispense(ctx, "ATM001", 90)
+	assert.Nil(t, err)
+}
+
+func TestParseFault(t *testing.T) {
+	f, err := ParseFault("timeout")
+	assert.Nil(t, err)
+	assert.Equal(t, FaultTimeout, f)
+	f, err = ParseFault("NONE")
+	assert.Nil(t, err)
+	assert.Equal(t, FaultNone, f)
+	_, err = ParseFault("FIRE")
+	assert.Equal(t, ErrUnknownFault, err)
+}
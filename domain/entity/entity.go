@@ -1,29 +1,613 @@
 package entity
 
+import (
+	"fmt"
+	"time"
+)
+
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "ACTIVE"
+	AccountFrozen AccountStatus = "FROZEN"
+	AccountClosed AccountStatus = "CLOSED"
+)
+
+type AccountType string
+
+const (
+	AccountSavings           AccountType = "SAVINGS"
+	AccountChecking          AccountType = "CHECKING"
+	AccountCheckingOverdraft AccountType = "CHECKING_OVERDRAFT"
+)
+
+// DefaultAccountType is the type of accounts that were not given one
+const DefaultAccountType = AccountChecking
+
+// DefaultTier is the tier of accounts that were not given one, tiers decide which fees are waived
+const DefaultTier = "STANDARD"
+
 type Account struct {
-	Name          string  `json:"name"`
-	AccountNumber string  `json:"accountNumber"`
-	PIN           string  `json:"pin"`
-	Balance       float64 `json:"balance"`
+	Name          string        `json:"name"`
+	AccountNumber string        `json:"accountNumber"`
+	PIN           string        `json:"pin"`
+	Balance       float64       `json:"balance"`
+	Status        AccountStatus `json:"status"`
+	Tier          string        `json:"tier,omitempty"`
+	Type          AccountType   `json:"type,omitempty"`
+	// Currency is the ISO code of the currency of the balance, empty for accounts opened in the base currency
+	Currency string `json:"currency,omitempty"`
+	// OverdraftLimit is how far below zero the balance can go, on CHECKING_OVERDRAFT accounts only
+	OverdraftLimit float64 `json:"overdraftLimit,omitempty"`
+	// OverdraftRate is the yearly interest rate in percent charged daily on a negative balance
+	OverdraftRate float64 `json:"overdraftRate,omitempty"`
+	// OverdraftInterestOn is the last day overdraft interest was charged, in YYYY-MM-DD format
+	OverdraftInterestOn string `json:"overdraftInterestOn,omitempty"`
+	// AccruedInterest is the interest earned and not posted yet, InterestAccruedOn the last day it was accrued
+	AccruedInterest   float64 `json:"accruedInterest,omitempty"`
+	InterestAccruedOn string  `json:"interestAccruedOn,omitempty"`
+	// TOTP is the authenticator app second factor of the account, nil until the customer enrols
+	TOTP *TOTP `json:"totp,omitempty"`
 }
 
+// TOTP is a time-based one-time password second factor. It is only enforced once the customer confirmed
+// the enrolment with a first code. LastCounter is the period of the last code used, so it cannot be used again.
+type TOTP struct {
+	Secret      string     `json:"secret"`
+	Enabled     bool       `json:"enabled"`
+	EnabledAt   *time.Time `json:"enabledAt,omitempty"`
+	LastCounter int64      `json:"lastCounter,omitempty"`
+	// Failures counts the wrong codes in a row, too many lock the step-up until LockedUntil
+	Failures    int        `json:"failures,omitempty"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+}
+
+// TOTPEnrolment is what the customer adds to an authenticator app, by scanning ProvisioningURI as a QR code
+// or typing Secret
+type TOTPEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// AccountResponse has both balances of the account : Balance is the ledger balance,
+// AvailableBalance is what is left of it once the active holds are taken out
 type AccountResponse struct {
-	Name          string  `json:"name"`
-	AccountNumber string  `json:"accountNumber"`
-	Balance       float64 `json:"balance"`
+	Name             string        `json:"name"`
+	AccountNumber    string        `json:"accountNumber"`
+	Balance          float64       `json:"balance"`
+	AvailableBalance float64       `json:"availableBalance"`
+	Status           AccountStatus `json:"status,omitempty"`
+	Tier             string        `json:"tier,omitempty"`
+	Type             AccountType   `json:"type,omitempty"`
+	Currency         string        `json:"currency,omitempty"`
+	OverdraftLimit   float64       `json:"overdraftLimit,omitempty"`
+	// TOTPEnabled tells that transfers and withdrawals above the step-up threshold need a one-time password
+	TOTPEnabled bool `json:"totpEnabled,omitempty"`
+	// OverdraftHeadroom is the part of the overdraft limit that is not used yet
+	OverdraftHeadroom float64 `json:"overdraftHeadroom,omitempty"`
+	// AccruedInterest is the interest earned and not posted to the balance yet
+	AccruedInterest float64 `json:"accruedInterest,omitempty"`
+	TransactionID   string  `json:"transactionId,omitempty"`
+	// Fee is the fee charged for the transaction, posted as its own FEE transaction
+	Fee float64 `json:"fee,omitempty"`
+	// Dispensed is the cash handed out by a withdrawal, less than its amount when the dispenser failed halfway
+	Dispensed float64 `json:"dispensed,omitempty"`
+	// ConvertedAmount is what the destination of a transfer to an account in another currency is credited, at FXRate
+	ConvertedAmount   float64 `json:"convertedAmount,omitempty"`
+	ConvertedCurrency string  `json:"convertedCurrency,omitempty"`
+	FXRate            float64 `json:"fxRate,omitempty"`
 }
 
 type Transfer struct {
-	FromAccountNumber string  `json:"fromAccountNumber"`
-	ToAccountNumber   string  `json:"toAccountNumber"`
-	ReferenceNumber   string  `json:"referenceNumber"`
-	Amount            float64 `json:"amount"`
+	FromAccountNumber string `json:"fromAccountNumber"`
+	ToAccountNumber   string `json:"toAccountNumber"`
+	// BankCode sends the transfer to an account of another bank through the interbank switch
+	BankCode string `json:"bankCode,omitempty"`
+	// BeneficiaryID transfers to a saved beneficiary of the source account instead of ToAccountNumber
+	BeneficiaryID string `json:"beneficiaryId,omitempty"`
+	// QuoteID converts the amount at the rate of an FX quote, for a transfer to an account in another currency
+	QuoteID         string  `json:"quoteId,omitempty"`
+	ReferenceNumber string  `json:"referenceNumber"`
+	Amount          float64 `json:"amount"`
 }
 
 func (a *Account) ToAccountResponse() *AccountResponse {
 	return &AccountResponse{
-		Name:          a.Name,
-		AccountNumber: a.AccountNumber,
-		Balance:       a.Balance,
+		Name:           a.Name,
+		AccountNumber:  a.AccountNumber,
+		Balance:        a.Balance,
+		Status:         a.Status,
+		Tier:           a.Tier,
+		Type:           a.Type,
+		Currency:       a.Currency,
+		OverdraftLimit: a.OverdraftLimit,
+		TOTPEnabled:    a.TOTP != nil && a.TOTP.Enabled,
+	}
+}
+
+type ATM struct {
+	ID              string    `json:"id"`
+	FastCashPresets []float64 `json:"fastCashPresets"`
+	Cash            float64   `json:"cash"`
+}
+
+type FastCashPreset struct {
+	Amount    float64 `json:"amount"`
+	Available bool    `json:"available"`
+}
+
+type TransactionType string
+
+const (
+	TransactionWithdraw    TransactionType = "WITHDRAW"
+	TransactionTransferOut TransactionType = "TRANSFER_OUT"
+	TransactionTransferIn  TransactionType = "TRANSFER_IN"
+	TransactionReversalIn  TransactionType = "REVERSAL_IN"
+	TransactionReversalOut TransactionType = "REVERSAL_OUT"
+	TransactionFee         TransactionType = "FEE"
+	// TransactionOverdraftInterest is the daily interest charged on a negative balance
+	TransactionOverdraftInterest TransactionType = "OVERDRAFT_INTEREST"
+	// TransactionInterest is the interest paid on the balance
+	TransactionInterest TransactionType = "INTEREST"
+	// TransactionBillPayment pays the bill of ReferenceNumber, the customer reference, to BillerCode
+	TransactionBillPayment TransactionType = "BILL_PAYMENT"
+)
+
+type ReversalStatus string
+
+const (
+	ReversalPartial ReversalStatus = "PARTIALLY_REVERSED"
+	ReversalFull    ReversalStatus = "REVERSED"
+)
+
+// Transaction is a balance change of one account. A transfer is recorded as
+// a TRANSFER_OUT on the source account followed by a TRANSFER_IN on the destination account.
+// Reversing a transaction gives the money back with a REVERSAL_IN, and takes it back from
+// the destination of a transfer with a REVERSAL_OUT.
+type Transaction struct {
+	ID                       string          `json:"id"`
+	ATMID                    string          `json:"atmId"`
+	AccountNumber            string          `json:"accountNumber"`
+	Type                     TransactionType `json:"type"`
+	Amount                   float64         `json:"amount"`
+	Currency                 string          `json:"currency,omitempty"`
+	CounterpartAccountNumber string          `json:"counterpartAccountNumber,omitempty"`
+	// CounterpartAmount is the amount of a transfer between accounts in different currencies in the currency
+	// of the counterpart account, converted at FXRate from the source account currency
+	CounterpartAmount float64 `json:"counterpartAmount,omitempty"`
+	FXRate            float64 `json:"fxRate,omitempty"`
+	// CounterpartBankCode is the bank of the counterpart account of an interbank transfer
+	CounterpartBankCode string    `json:"counterpartBankCode,omitempty"`
+	ReferenceNumber     string    `json:"referenceNumber,omitempty"`
+	Balance             float64   `json:"balance"`
+	Time                time.Time `json:"time"`
+
+	// ReversalOf is the ID of the transaction a reversal undoes
+	ReversalOf     string         `json:"reversalOf,omitempty"`
+	Reason         string         `json:"reason,omitempty"`
+	ReversalStatus ReversalStatus `json:"reversalStatus,omitempty"`
+	ReversedAmount float64        `json:"reversedAmount,omitempty"`
+	// FeeOf is the ID of the transaction a fee is charged for
+	FeeOf string `json:"feeOf,omitempty"`
+	// BillerCode is the biller a bill payment is paid to, and BillerReference the reference the biller gave to it
+	BillerCode      string `json:"billerCode,omitempty"`
+	BillerReference string `json:"billerReference,omitempty"`
+}
+
+type Receipt struct {
+	TransactionID            string          `json:"transactionId"`
+	ATMID                    string          `json:"atmId"`
+	AccountNumber            string          `json:"accountNumber"`
+	Time                     time.Time       `json:"time"`
+	Type                     TransactionType `json:"type"`
+	Amount                   float64         `json:"amount"`
+	Currency                 string          `json:"currency,omitempty"`
+	DestinationAccountNumber string          `json:"destinationAccountNumber,omitempty"`
+	DestinationBank          string          `json:"destinationBank,omitempty"`
+	Biller                   string          `json:"biller,omitempty"`
+	BillerReference          string          `json:"billerReference,omitempty"`
+	Reference                string          `json:"reference"`
+	Fee                      float64         `json:"fee,omitempty"`
+	Balance                  float64         `json:"balance"`
+	// ConvertedAmount is the amount in ConvertedCurrency of a transfer between accounts in different currencies
+	ConvertedAmount   float64 `json:"convertedAmount,omitempty"`
+	ConvertedCurrency string  `json:"convertedCurrency,omitempty"`
+	FXRate            float64 `json:"fxRate,omitempty"`
+}
+
+// FormatTransactionID gives the ID of the seq-th transaction
+func FormatTransactionID(seq int) string {
+	return fmt.Sprintf("TRX%08d", seq)
+}
+
+// SignedAmount is the change of the account balance, negative when money goes out
+func (t *Transaction) SignedAmount() float64 {
+	switch t.Type {
+	case TransactionWithdraw, TransactionTransferOut, TransactionReversalOut, TransactionFee, TransactionOverdraftInterest:
+		return -t.Amount
 	}
+	return t.Amount
+}
+
+type Statement struct {
+	AccountNumber  string          `json:"accountNumber"`
+	Name           string          `json:"name"`
+	Currency       string          `json:"currency,omitempty"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance float64         `json:"openingBalance"`
+	ClosingBalance float64         `json:"closingBalance"`
+	TotalDebit     float64         `json:"totalDebit"`
+	TotalCredit    float64         `json:"totalCredit"`
+	Lines          []StatementLine `json:"lines"`
+}
+
+type StatementLine struct {
+	TransactionID  string          `json:"transactionId"`
+	Time           time.Time       `json:"time"`
+	Type           TransactionType `json:"type"`
+	Description    string          `json:"description"`
+	Debit          float64         `json:"debit"`
+	Credit         float64         `json:"credit"`
+	Balance        float64         `json:"balance"`
+	ReversalStatus ReversalStatus  `json:"reversalStatus,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun   bool          `json:"dryRun"`
+	Total    int           `json:"total"`
+	Imported int           `json:"imported"`
+	Rejected int           `json:"rejected"`
+	Errors   []ImportError `json:"errors"`
+}
+
+type ImportError struct {
+	Line          int    `json:"line"`
+	AccountNumber string `json:"accountNumber,omitempty"`
+	Message       string `json:"message"`
+}
+
+// Posting moves Amount on a ledger account, debits are positive and credits negative
+type Posting struct {
+	Account string  `json:"account"`
+	Amount  float64 `json:"amount"`
+}
+
+// JournalEntry is a balanced set of postings, the postings of an entry always sum to zero
+type JournalEntry struct {
+	ID            string    `json:"id"`
+	Time          time.Time `json:"time"`
+	Description   string    `json:"description"`
+	TransactionID string    `json:"transactionId,omitempty"`
+	Postings      []Posting `json:"postings"`
+}
+
+// FormatJournalEntryID gives the ID of the seq-th journal entry
+func FormatJournalEntryID(seq int) string {
+	return fmt.Sprintf("JE%08d", seq)
+}
+
+type LedgerReport struct {
+	Balanced   bool               `json:"balanced"`
+	Total      float64            `json:"total"`
+	Balances   map[string]float64 `json:"balances"`
+	Violations []string           `json:"violations"`
+}
+
+type DisputeStatus string
+
+const (
+	DisputeOpen     DisputeStatus = "OPEN"
+	DisputeReversed DisputeStatus = "REVERSED"
+	DisputeRejected DisputeStatus = "REJECTED"
+)
+
+// Dispute is a customer's claim that a transaction went wrong, an admin resolves it
+// by reversing the transaction or rejecting the claim
+type Dispute struct {
+	ID            string        `json:"id"`
+	TransactionID string        `json:"transactionId"`
+	AccountNumber string        `json:"accountNumber"`
+	Reason        string        `json:"reason"`
+	Status        DisputeStatus `json:"status"`
+	Resolution    string        `json:"resolution,omitempty"`
+	ReversalID    string        `json:"reversalId,omitempty"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	ResolvedAt    *time.Time    `json:"resolvedAt,omitempty"`
+}
+
+// FormatDisputeID gives the ID of the seq-th dispute
+func FormatDisputeID(seq int) string {
+	return fmt.Sprintf("DSP%08d", seq)
+}
+
+type HoldType string
+
+const (
+	HoldPendingWithdraw    HoldType = "PENDING_WITHDRAW"
+	HoldPendingBillPayment HoldType = "PENDING_BILL_PAYMENT"
+	HoldPendingInterbank   HoldType = "PENDING_INTERBANK_TRANSFER"
+	HoldDeposit            HoldType = "DEPOSIT"
+	HoldAdmin              HoldType = "ADMIN"
+	HoldCardlessWithdraw   HoldType = "CARDLESS_WITHDRAW"
+)
+
+// Hold reserves Amount of an account's balance without posting anything,
+// until it is released or it expires. A hold without ExpiresAt never expires.
+type Hold struct {
+	ID            string     `json:"id"`
+	AccountNumber string     `json:"accountNumber"`
+	Type          HoldType   `json:"type"`
+	Amount        float64    `json:"amount"`
+	Reason        string     `json:"reason"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	ReleasedAt    *time.Time `json:"releasedAt,omitempty"`
+}
+
+// Active reports whether the hold still reduces the available balance at t
+func (h *Hold) Active(t time.Time) bool {
+	return h.ReleasedAt == nil && (h.ExpiresAt == nil || t.Before(*h.ExpiresAt))
+}
+
+// FormatHoldID gives the ID of the seq-th hold
+func FormatHoldID(seq int) string {
+	return fmt.Sprintf("HLD%08d", seq)
+}
+
+// FeeQuote is the fee an operation would be charged, shown before the customer confirms it
+type FeeQuote struct {
+	Operation string  `json:"operation"`
+	Network   string  `json:"network"`
+	Amount    float64 `json:"amount"`
+	Fee       float64 `json:"fee"`
+}
+
+// AccountTypeRules are the limits every account of a type follows
+type AccountTypeRules struct {
+	Type AccountType `json:"type"`
+	// WithdrawLimit is the maximum amount of one withdrawal
+	WithdrawLimit float64 `json:"withdrawLimit"`
+	// MinimumBalance is the balance withdrawals and transfers cannot go below
+	MinimumBalance     float64 `json:"minimumBalance"`
+	CanTransferOut     bool    `json:"canTransferOut"`
+	CanReceiveTransfer bool    `json:"canReceiveTransfer"`
+	Overdraft          bool    `json:"overdraft"`
+}
+
+type CardStatus string
+
+const (
+	CardActive  CardStatus = "ACTIVE"
+	CardBlocked CardStatus = "BLOCKED"
+)
+
+// Card is the credential a customer logs in with. A card can access several accounts,
+// the first one is where the session starts, and an account can have several cards.
+type Card struct {
+	Number         string     `json:"number"`
+	PIN            string     `json:"pin"`
+	Status         CardStatus `json:"status"`
+	AccountNumbers []string   `json:"accountNumbers"`
+	// ExpiresAt is the start of the month after the expiry month printed on the card
+	ExpiresAt time.Time `json:"expiresAt"`
+	IssuedAt  time.Time `json:"issuedAt"`
+}
+
+type CardResponse struct {
+	Number         string     `json:"number"`
+	Status         CardStatus `json:"status"`
+	AccountNumbers []string   `json:"accountNumbers"`
+	Expiry         string     `json:"expiry"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	IssuedAt       time.Time  `json:"issuedAt"`
+}
+
+// CardLogin is what the customer enters at the Welcome screen
+type CardLogin struct {
+	CardNumber string `json:"cardNumber"`
+	PIN        string `json:"pin"`
+}
+
+// Expired reports whether the card can no longer be used at t
+func (c *Card) Expired(t time.Time) bool {
+	return !t.Before(c.ExpiresAt)
+}
+
+func (c *Card) ToCardResponse() *CardResponse {
+	return &CardResponse{
+		Number:         c.Number,
+		Status:         c.Status,
+		AccountNumbers: append([]string(nil), c.AccountNumbers...),
+		Expiry:         c.ExpiresAt.AddDate(0, 0, -1).Format("01/06"),
+		ExpiresAt:      c.ExpiresAt,
+		IssuedAt:       c.IssuedAt,
+	}
+}
+
+type StandingOrderFrequency string
+
+const (
+	StandingOrderWeekly  StandingOrderFrequency = "WEEKLY"
+	StandingOrderMonthly StandingOrderFrequency = "MONTHLY"
+)
+
+type StandingOrderStatus string
+
+const (
+	StandingOrderActive    StandingOrderStatus = "ACTIVE"
+	StandingOrderCancelled StandingOrderStatus = "CANCELLED"
+	// StandingOrderCompleted is an order past its end date
+	StandingOrderCompleted StandingOrderStatus = "COMPLETED"
+)
+
+// StandingOrder transfers Amount to ToAccountNumber every week or month until EndDate.
+// Day is the day of the week (1 is Monday, 7 is Sunday) of a WEEKLY order, or the day of the month
+// of a MONTHLY order, moved to the last day of shorter months.
+type StandingOrder struct {
+	ID                string                 `json:"id"`
+	FromAccountNumber string                 `json:"fromAccountNumber"`
+	ToAccountNumber   string                 `json:"toAccountNumber"`
+	Amount            float64                `json:"amount"`
+	ReferenceNumber   string                 `json:"referenceNumber,omitempty"`
+	Frequency         StandingOrderFrequency `json:"frequency"`
+	Day               int                    `json:"day"`
+	// EndDate is the last day, YYYY-MM-DD, the order pays on. Without it the order runs until it is cancelled.
+	EndDate string              `json:"endDate,omitempty"`
+	Status  StandingOrderStatus `json:"status"`
+	// DueOn is the day, YYYY-MM-DD, of the payment not made yet because of insufficient balance,
+	// it is retried every day for a few days
+	DueOn       string             `json:"dueOn,omitempty"`
+	Executions  []StandingOrderRun `json:"executions"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	CancelledAt *time.Time         `json:"cancelledAt,omitempty"`
+}
+
+// StandingOrderRun is one attempt, on Day, to pay the payment of a standing order due on DueOn
+type StandingOrderRun struct {
+	DueOn         string    `json:"dueOn"`
+	Day           string    `json:"day"`
+	Time          time.Time `json:"time"`
+	TransactionID string    `json:"transactionId,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// FormatStandingOrderID gives the ID of the seq-th standing order
+func FormatStandingOrderID(seq int) string {
+	return fmt.Sprintf("STO%08d", seq)
+}
+
+// Notification is a message for the account holder, like a standing order that could not be paid
+type Notification struct {
+	ID            string    `json:"id"`
+	AccountNumber string    `json:"accountNumber"`
+	Message       string    `json:"message"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// FormatNotificationID gives the ID of the seq-th notification
+func FormatNotificationID(seq int) string {
+	return fmt.Sprintf("NTF%08d", seq)
+}
+
+// Beneficiary is a destination account saved by the account holder to transfer to it without typing it again
+type Beneficiary struct {
+	ID              string `json:"id"`
+	AccountNumber   string `json:"accountNumber"`
+	Nickname        string `json:"nickname"`
+	ToAccountNumber string `json:"toAccountNumber"`
+	BankCode        string `json:"bankCode,omitempty"`
+	// MaskedName is the name of the destination account holder with only the initials shown
+	MaskedName string     `json:"maskedName"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RemovedAt  *time.Time `json:"removedAt,omitempty"`
+}
+
+// FormatBeneficiaryID gives the ID of the seq-th beneficiary
+func FormatBeneficiaryID(seq int) string {
+	return fmt.Sprintf("BEN%08d", seq)
+}
+
+// BillPayment is a bill the customer pays at the ATM, Amount can be left at 0 to pay the amount due of a FIXED biller
+type BillPayment struct {
+	BillerCode        string  `json:"billerCode"`
+	CustomerReference string  `json:"customerReference"`
+	Amount            float64 `json:"amount"`
+}
+
+// BillPaymentReceipt is the outcome of a bill payment with the receipt data of the biller
+type BillPaymentReceipt struct {
+	TransactionID     string    `json:"transactionId"`
+	BillerCode        string    `json:"billerCode"`
+	BillerName        string    `json:"billerName"`
+	CustomerReference string    `json:"customerReference"`
+	CustomerName      string    `json:"customerName"`
+	Amount            float64   `json:"amount"`
+	BillerReference   string    `json:"billerReference"`
+	Time              time.Time `json:"time"`
+	Balance           float64   `json:"balance"`
+	AvailableBalance  float64   `json:"availableBalance"`
+}
+
+// TransferInquiry is the destination of a transfer as shown before the customer confirms it
+type TransferInquiry struct {
+	BankCode      string `json:"bankCode"`
+	BankName      string `json:"bankName"`
+	AccountNumber string `json:"accountNumber"`
+	MaskedName    string `json:"maskedName"`
+}
+
+// FXQuote is the rate a transfer to an account in another currency is converted at, shown before the customer
+// confirms the transfer. The rate is locked until ExpiresAt, and a quote can only be used once.
+type FXQuote struct {
+	ID                string     `json:"id"`
+	FromAccountNumber string     `json:"fromAccountNumber"`
+	ToAccountNumber   string     `json:"toAccountNumber"`
+	FromCurrency      string     `json:"fromCurrency"`
+	ToCurrency        string     `json:"toCurrency"`
+	Amount            float64    `json:"amount"`
+	Rate              float64    `json:"rate"`
+	ConvertedAmount   float64    `json:"convertedAmount"`
+	RateUpdatedAt     time.Time  `json:"rateUpdatedAt"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	UsedAt            *time.Time `json:"usedAt,omitempty"`
+}
+
+// FormatFXQuoteID gives the ID of the seq-th FX quote
+func FormatFXQuoteID(seq int) string {
+	return fmt.Sprintf("FXQ%08d", seq)
+}
+
+type CardlessWithdrawalStatus string
+
+const (
+	CardlessPending   CardlessWithdrawalStatus = "PENDING"
+	CardlessRedeemed  CardlessWithdrawalStatus = "REDEEMED"
+	CardlessCancelled CardlessWithdrawalStatus = "CANCELLED"
+	CardlessExpired   CardlessWithdrawalStatus = "EXPIRED"
+	// CardlessBlocked is a code that can no longer be used after too many wrong PINs
+	CardlessBlocked CardlessWithdrawalStatus = "BLOCKED"
+)
+
+// CardlessWithdrawal is a withdrawal staged from a phone, collected at any ATM with Code and PIN until ExpiresAt.
+// Amount and its fee are held on the account until the code is redeemed, cancelled or expires.
+type CardlessWithdrawal struct {
+	ID            string  `json:"id"`
+	AccountNumber string  `json:"accountNumber"`
+	Amount        float64 `json:"amount"`
+	Code          string  `json:"code"`
+	// PIN is the secondary PIN chosen for this withdrawal, it is not the PIN of the card
+	PIN string `json:"pin,omitempty"`
+	// RecipientPhone is the phone the code is sent to, when someone else collects the cash
+	RecipientPhone string                   `json:"recipientPhone,omitempty"`
+	HoldID         string                   `json:"holdId"`
+	Status         CardlessWithdrawalStatus `json:"status"`
+	FailedAttempts int                      `json:"failedAttempts,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	ExpiresAt      time.Time                `json:"expiresAt"`
+	RedeemedAt     *time.Time               `json:"redeemedAt,omitempty"`
+	ATMID          string                   `json:"atmId,omitempty"`
+	TransactionID  string                   `json:"transactionId,omitempty"`
+	// Dispensed is less than Amount when the ATM could only dispense part of it, the rest stays in the account
+	Dispensed   float64    `json:"dispensed,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+}
+
+// CardlessWithdrawalRequest stages a cardless withdrawal, the code expires after ExpiresInMinutes or the default expiry
+type CardlessWithdrawalRequest struct {
+	Amount           float64 `json:"amount"`
+	PIN              string  `json:"pin"`
+	RecipientPhone   string  `json:"recipientPhone,omitempty"`
+	ExpiresInMinutes int     `json:"expiresInMinutes,omitempty"`
+}
+
+// CardlessRedemption is what the customer enters at the ATM to collect a cardless withdrawal
+type CardlessRedemption struct {
+	Code string `json:"code"`
+	PIN  string `json:"pin"`
+}
+
+// FormatCardlessWithdrawalID gives the ID of the seq-th cardless withdrawal
+func FormatCardlessWithdrawalID(seq int) string {
+	return fmt.Sprintf("CLW%08d", seq)
 }
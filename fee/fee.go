@@ -0,0 +1,194 @@
+package fee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+)
+
+type Operation string
+
+const (
+	Withdraw Operation = "WITHDRAW"
+	Transfer Operation = "TRANSFER"
+)
+
+// Network is how an operation reaches the bank : on us when it stays in the bank, interbank when it goes
+// through the interbank network, like a transfer to another bank or a withdrawal with the card of another bank
+type Network string
+
+const (
+	OnUs      Network = "ON_US"
+	Interbank Network = "INTERBANK"
+)
+
+// Tier charges Fee on amounts up to UpTo, the last tier can leave UpTo at 0 to have no limit
+type Tier struct {
+	UpTo float64 `json:"upTo"`
+	Fee  float64 `json:"fee"`
+}
+
+// Rule is the fee of one operation : Flat plus Percent of the amount, kept between Min and Max
+// when they are set, plus the fee of the first tier the amount fits in.
+// Accounts of WaivedTiers never pay it, and the first FreePerMonth operations of a month are free.
+// A rule without a Network is the fee of the operation on both networks, unless a rule of the same
+// operation names the network.
+type Rule struct {
+	Operation    Operation `json:"operation"`
+	Network      Network   `json:"network,omitempty"`
+	Flat         float64   `json:"flat,omitempty"`
+	Percent      float64   `json:"percent,omitempty"`
+	Min          float64   `json:"min,omitempty"`
+	Max          float64   `json:"max,omitempty"`
+	Tiers        []Tier    `json:"tiers,omitempty"`
+	FreePerMonth int       `json:"freePerMonth,omitempty"`
+	WaivedTiers  []string  `json:"waivedTiers,omitempty"`
+}
+
+// Usage is what the engine needs to know about the account paying the fee
+type Usage struct {
+	AccountTier string
+	// Network of the operation, on us when it is not set
+	Network Network
+	// ThisMonth is the number of operations of the same kind the account already did this month
+	ThisMonth int
+}
+
+// Engine evaluates the fee rules, an engine without rules charges nothing
+type Engine struct {
+	mu    sync.RWMutex
+	rules map[ruleKey]Rule
+}
+
+type ruleKey struct {
+	operation Operation
+	network   Network
+}
+
+func New(rules ...Rule) (*Engine, error) {
+	e := &Engine{}
+	if err := e.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Load reads the rules from a JSON file, an empty path gives an engine without rules
+func Load(path string) (*Engine, error) {
+	if path == "" {
+		return New()
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling fee rules : %w", err)
+	}
+	return New(rules...)
+}
+
+// SetRules replaces all the rules, there can be one rule per operation and network
+func (e *Engine) SetRules(rules []Rule) error {
+	byOperation := make(map[ruleKey]Rule, len(rules))
+	for _, r := range rules {
+		key := ruleKey{r.Operation, r.Network}
+		if err := validate(r); err != nil {
+			return fmt.Errorf("%s fee : %w", r.Operation, err)
+		} else if _, ok := byOperation[key]; ok {
+			return fmt.Errorf("%s fee : more than one rule for %s", r.Operation, networkName(r.Network))
+		}
+		byOperation[key] = r
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = byOperation
+	return nil
+}
+
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	rules := make([]Rule, 0, len(e.rules))
+	for _, r := range e.rules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Operation != rules[j].Operation {
+			return rules[i].Operation < rules[j].Operation
+		}
+		return rules[i].Network < rules[j].Network
+	})
+	return rules
+}
+
+// Fee is the fee charged for an operation of amount, by the rule of its network or else the rule of any network
+func (e *Engine) Fee(op Operation, amount float64, usage Usage) float64 {
+	if usage.Network == "" {
+		usage.Network = OnUs
+	}
+	e.mu.RLock()
+	r, ok := e.rules[ruleKey{op, usage.Network}]
+	if !ok {
+		r, ok = e.rules[ruleKey{op, ""}]
+	}
+	e.mu.RUnlock()
+	if !ok || usage.ThisMonth < r.FreePerMonth {
+		return 0
+	}
+	for _, t := range r.WaivedTiers {
+		if t == usage.AccountTier {
+			return 0
+		}
+	}
+	fee := r.Flat
+	if r.Percent > 0 {
+		pct := amount * r.Percent / 100
+		if r.Min > 0 {
+			pct = math.Max(pct, r.Min)
+		}
+		if r.Max > 0 {
+			pct = math.Min(pct, r.Max)
+		}
+		fee += pct
+	}
+	for _, t := range r.Tiers {
+		if t.UpTo == 0 || amount <= t.UpTo {
+			fee += t.Fee
+			break
+		}
+	}
+	return math.Round(fee*100) / 100
+}
+
+func validate(r Rule) error {
+	if r.Operation == "" {
+		return errors.New("operation is required")
+	} else if r.Network != "" && r.Network != OnUs && r.Network != Interbank {
+		return errors.New("network should be ON_US or INTERBANK")
+	} else if r.Flat < 0 || r.Percent < 0 || r.Min < 0 || r.Max < 0 || r.FreePerMonth < 0 {
+		return errors.New("fees cannot be negative")
+	} else if r.Max > 0 && r.Min > r.Max {
+		return errors.New("min cannot be more than max")
+	}
+	for i, t := range r.Tiers {
+		if t.Fee < 0 || t.UpTo < 0 {
+			return errors.New("fees cannot be negative")
+		} else if i > 0 && (r.Tiers[i-1].UpTo == 0 || (t.UpTo != 0 && t.UpTo <= r.Tiers[i-1].UpTo)) {
+			return errors.New("tiers should be in increasing order, only the last one can have no limit")
+		}
+	}
+	return nil
+}
+
+func networkName(n Network) string {
+	if n == "" {
+		return "any network"
+	}
+	return string(n)
+}
@@ -0,0 +1,73 @@
+package fee
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFee_FlatAndPercentage(t *testing.T) {
+	e, err := New(Rule{Operation: Transfer, Flat: 1, Percent: 1, Min: 0.5, Max: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, e.Fee(Transfer, 10, Usage{}))
+	assert.Equal(t, 4.0, e.Fee(Transfer, 300, Usage{}))
+	assert.Equal(t, 6.0, e.Fee(Transfer, 1000, Usage{}))
+	assert.Equal(t, 0.0, e.Fee(Withdraw, 1000, Usage{}))
+}
+
+func TestFee_Tiered(t *testing.T) {
+	e, err := New(Rule{Operation: Withdraw, Tiers: []Tier{{UpTo: 100, Fee: 1}, {UpTo: 500, Fee: 2}, {Fee: 3}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, e.Fee(Withdraw, 100, Usage{}))
+	assert.Equal(t, 2.0, e.Fee(Withdraw, 110, Usage{}))
+	assert.Equal(t, 3.0, e.Fee(Withdraw, 1000, Usage{}))
+}
+
+func TestFee_WaiversAndFreeOperations(t *testing.T) {
+	e, _ := New(Rule{Operation: Withdraw, Flat: 2, FreePerMonth: 3, WaivedTiers: []string{"PREMIUM"}})
+	assert.Equal(t, 0.0, e.Fee(Withdraw, 50, Usage{AccountTier: "STANDARD", ThisMonth: 2}))
+	assert.Equal(t, 2.0, e.Fee(Withdraw, 50, Usage{AccountTier: "STANDARD", ThisMonth: 3}))
+	assert.Equal(t, 0.0, e.Fee(Withdraw, 50, Usage{AccountTier: "PREMIUM", ThisMonth: 10}))
+}
+
+func TestFee_Network(t *testing.T) {
+	e, err := New(Rule{Operation: Transfer, Flat: 1}, Rule{Operation: Transfer, Network: Interbank, Flat: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, e.Fee(Transfer, 100, Usage{}))
+	assert.Equal(t, 1.0, e.Fee(Transfer, 100, Usage{Network: OnUs}))
+	assert.Equal(t, 3.0, e.Fee(Transfer, 100, Usage{Network: Interbank}))
+
+	e, _ = New(Rule{Operation: Withdraw, Network: Interbank, Flat: 2})
+	assert.Equal(t, 0.0, e.Fee(Withdraw, 100, Usage{}))
+	assert.Equal(t, 2.0, e.Fee(Withdraw, 100, Usage{Network: Interbank}))
+}
+
+func TestSetRules_Validation(t *testing.T) {
+	_, err := New(Rule{Operation: Withdraw, Flat: -1})
+	assert.NotNil(t, err)
+	_, err = New(Rule{Operation: Withdraw}, Rule{Operation: Withdraw})
+	assert.NotNil(t, err)
+	_, err = New(Rule{Operation: Withdraw, Network: Interbank}, Rule{Operation: Withdraw, Network: Interbank})
+	assert.NotNil(t, err)
+	_, err = New(Rule{Operation: Withdraw, Network: "SWIFT"})
+	assert.NotNil(t, err)
+	_, err = New(Rule{Operation: Withdraw, Tiers: []Tier{{Fee: 1}, {UpTo: 100, Fee: 2}}})
+	assert.NotNil(t, err)
+	_, err = New(Rule{Operation: Withdraw, Percent: 1, Min: 5, Max: 2})
+	assert.NotNil(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	e, err := Load("")
+	assert.Nil(t, err)
+	assert.Empty(t, e.Rules())
+
+	path := filepath.Join(t.TempDir(), "fees.json")
+	os.WriteFile(path, []byte(`[{"operation":"TRANSFER","flat":1},{"operation":"WITHDRAW","percent":1}]`), 0o600)
+	e, err = Load(path)
+	assert.Nil(t, err)
+	assert.Len(t, e.Rules(), 2)
+	assert.Equal(t, Transfer, e.Rules()[0].Operation)
+}
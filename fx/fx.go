@@ -0,0 +1,216 @@
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+)
+
+// Base is the currency rates are quoted from, and the currency of accounts opened without one
+const Base = "USD"
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	currencyPattern    = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Rate is how much of Currency one unit of the Base currency buys, as set at UpdatedAt
+type Rate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Conversion is the rate to convert an amount of From into To, UpdatedAt is the time of the oldest rate it uses
+type Conversion struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Convert is amount of From in To, rounded to the cent
+func (c Conversion) Convert(amount float64) float64 {
+	return math.Round(amount*c.Rate*100) / 100
+}
+
+// Provider keeps the exchange rates, a provider without rates only knows the Base currency
+type Provider struct {
+	mu    sync.RWMutex
+	rates map[string]Rate
+}
+
+// Default are the rates of a provider loaded without a file
+func Default() []Rate {
+	return []Rate{
+		{Currency: "EUR", Rate: 0.92},
+		{Currency: "GBP", Rate: 0.79},
+		{Currency: "SGD", Rate: 1.35},
+	}
+}
+
+func New(rates ...Rate) (*Provider, error) {
+	p := &Provider{}
+	if err := p.SetRates(rates); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Load reads the rates from a JSON file, an empty path gives the Default rates.
+// Rates without an updatedAt are as of the time c tells when they are loaded.
+func Load(path string, c clock.Clock) (*Provider, error) {
+	var rates []Rate
+	if path == "" {
+		rates = Default()
+	} else {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &rates); err != nil {
+			return nil, fmt.Errorf("failed unmarshalling exchange rates : %w", err)
+		}
+	}
+	now := c.Now()
+	for i := range rates {
+		if rates[i].UpdatedAt.IsZero() {
+			rates[i].UpdatedAt = now
+		}
+	}
+	return New(rates...)
+}
+
+// SetRates replaces all the rates, there can be one rate per currency
+func (p *Provider) SetRates(rates []Rate) error {
+	byCurrency := make(map[string]Rate, len(rates))
+	for _, r := range rates {
+		if err := validate(r); err != nil {
+			return err
+		} else if _, ok := byCurrency[r.Currency]; ok {
+			return fmt.Errorf("%s rate : more than one rate", r.Currency)
+		}
+		byCurrency[r.Currency] = r
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates = byCurrency
+	return nil
+}
+
+// SetRate adds the rate of a currency, or replaces its current rate
+func (p *Provider) SetRate(r Rate) error {
+	if err := validate(r); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates[r.Currency] = r
+	return nil
+}
+
+func validate(r Rate) error {
+	if !currencyPattern.MatchString(r.Currency) {
+		return fmt.Errorf("%q is not a currency code like EUR", r.Currency)
+	} else if r.Currency == Base {
+		return fmt.Errorf("%s rate : the rate of the base currency is always 1", r.Currency)
+	} else if r.Rate <= 0 {
+		return fmt.Errorf("%s rate : rate should be positive", r.Currency)
+	}
+	return nil
+}
+
+// Rates lists the rates sorted by currency
+func (p *Provider) Rates() []Rate {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	rates := make([]Rate, 0, len(p.rates))
+	for _, r := range p.rates {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Currency < rates[j].Currency
+	})
+	return rates
+}
+
+// Supported reports whether accounts can be opened in currency
+func (p *Provider) Supported(currency string) bool {
+	_, err := p.rate(currency)
+	return err == nil
+}
+
+// Conversion is the current rate to convert From into To, through the Base currency
+func (p *Provider) Conversion(from, to string) (Conversion, error) {
+	fromRate, err := p.rate(from)
+	if err != nil {
+		return Conversion{}, err
+	}
+	toRate, err := p.rate(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+	updatedAt := fromRate.UpdatedAt
+	if updatedAt.IsZero() || (!toRate.UpdatedAt.IsZero() && toRate.UpdatedAt.Before(updatedAt)) {
+		updatedAt = toRate.UpdatedAt
+	}
+	return Conversion{
+		From:      from,
+		To:        to,
+		Rate:      math.Round(toRate.Rate/fromRate.Rate*1e6) / 1e6,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func (p *Provider) rate(currency string) (Rate, error) {
+	if currency == Base {
+		return Rate{Currency: Base, Rate: 1}, nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	r, ok := p.rates[currency]
+	if !ok {
+		return Rate{}, ErrUnknownCurrency
+	}
+	return r, nil
+}
+
+// symbols are only ASCII, receipt printers and the statement PDF cannot print the other symbols
+var symbols = map[string]string{
+	"USD": "$",
+	"SGD": "S$",
+}
+
+// Format writes amount with the symbol of currency and cents, like $12.50.
+// Currencies without a symbol are written with their code, like EUR 12.50, and an empty currency is Base.
+func Format(currency string, amount float64) string {
+	return prefix(currency, amount) + fmt.Sprintf("%.2f", math.Abs(amount))
+}
+
+// FormatWhole writes amount like Format, without the cents
+func FormatWhole(currency string, amount float64) string {
+	return prefix(currency, amount) + fmt.Sprintf("%0.f", math.Abs(amount))
+}
+
+func prefix(currency string, amount float64) string {
+	if currency == "" {
+		currency = Base
+	}
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	if symbol, ok := symbols[currency]; ok {
+		return sign + symbol
+	}
+	return sign + strings.ToUpper(currency) + " "
+}
@@ -0,0 +1,74 @@
+package fx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversion_ThroughBase(t *testing.T) {
+	eurAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	gbpAt := time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)
+	p, err := New(Rate{Currency: "EUR", Rate: 0.8, UpdatedAt: eurAt}, Rate{Currency: "GBP", Rate: 0.6, UpdatedAt: gbpAt})
+	assert.Nil(t, err)
+
+	c, err := p.Conversion("USD", "EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, Conversion{From: "USD", To: "EUR", Rate: 0.8, UpdatedAt: eurAt}, c)
+	assert.Equal(t, 16.0, c.Convert(20))
+
+	c, _ = p.Conversion("EUR", "USD")
+	assert.Equal(t, 1.25, c.Rate)
+	c, _ = p.Conversion("EUR", "GBP")
+	assert.Equal(t, 0.75, c.Rate)
+	assert.Equal(t, eurAt, c.UpdatedAt)
+	assert.Equal(t, 7.5, c.Convert(10))
+
+	_, err = p.Conversion("USD", "JPY")
+	assert.Equal(t, ErrUnknownCurrency, err)
+	assert.True(t, p.Supported("USD"))
+	assert.False(t, p.Supported("JPY"))
+}
+
+func TestSetRate(t *testing.T) {
+	p, _ := New(Default()...)
+	at := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, p.SetRate(Rate{Currency: "EUR", Rate: 0.95, UpdatedAt: at}))
+	c, _ := p.Conversion("USD", "EUR")
+	assert.Equal(t, 0.95, c.Rate)
+	assert.Equal(t, at, c.UpdatedAt)
+
+	assert.NotNil(t, p.SetRate(Rate{Currency: "USD", Rate: 2}))
+	assert.NotNil(t, p.SetRate(Rate{Currency: "eur", Rate: 1}))
+	assert.NotNil(t, p.SetRate(Rate{Currency: "JPY", Rate: 0}))
+	_, err := New(Rate{Currency: "EUR", Rate: 1}, Rate{Currency: "EUR", Rate: 2})
+	assert.NotNil(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	loadedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	p, err := Load("", clock.NewFake(loadedAt))
+	assert.Nil(t, err)
+	assert.Equal(t, len(Default()), len(p.Rates()))
+	assert.Equal(t, loadedAt, p.Rates()[0].UpdatedAt)
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	os.WriteFile(path, []byte(`[{"currency":"JPY","rate":150,"updatedAt":"2026-10-01T09:00:00Z"}]`), 0o600)
+	p, err = Load(path, clock.Real())
+	assert.Nil(t, err)
+	assert.Equal(t, "JPY", p.Rates()[0].Currency)
+	assert.False(t, p.Supported("EUR"))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "$12.50", Format("USD", 12.5))
+	assert.Equal(t, "$12.50", Format("", 12.5))
+	assert.Equal(t, "EUR 12.50", Format("EUR", 12.5))
+	assert.Equal(t, "-S$3.00", Format("SGD", -3))
+	assert.Equal(t, "JPY 1500.00", Format("JPY", 1500))
+	assert.Equal(t, "GBP 40", FormatWhole("GBP", 40))
+}
@@ -0,0 +1,130 @@
+package interbank
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownBank     = errors.New("unknown bank code")
+	ErrAccountNotFound = errors.New("account not found at the destination bank")
+	ErrDeclined        = errors.New("transfer declined by the destination bank")
+	ErrTimeout         = errors.New("destination bank did not answer in time")
+)
+
+// Request is a transfer sent through the switch from an account of FromBank to an account of ToBank
+type Request struct {
+	ID          string  `json:"id"`
+	FromBank    string  `json:"fromBank"`
+	FromAccount string  `json:"fromAccount"`
+	FromName    string  `json:"fromName"`
+	ToBank      string  `json:"toBank"`
+	ToAccount   string  `json:"toAccount"`
+	Amount      float64 `json:"amount"`
+	Reference   string  `json:"reference,omitempty"`
+}
+
+// Bank is a member of the switch network. Inquire gives the name of the holder of an account, or ErrDeclined
+// when the account cannot receive transfers, Credit pays a transfer into one of its accounts.
+type Bank interface {
+	Code() string
+	Name() string
+	Inquire(ctx context.Context, acctNbr string) (string, error)
+	Credit(ctx context.Context, req Request) error
+}
+
+// BankInfo describes a member of the network
+type BankInfo struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Switch routes inquiries and transfers to the bank of their bank code.
+// A bank that does not answer within the timeout fails with ErrTimeout.
+type Switch struct {
+	mu      sync.RWMutex
+	timeout time.Duration
+	banks   map[string]Bank
+}
+
+func NewSwitch(timeout time.Duration) *Switch {
+	return &Switch{timeout: timeout, banks: make(map[string]Bank)}
+}
+
+// Register adds bank to the network, or replaces the bank with the same code
+func (s *Switch) Register(bank Bank) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.banks[bank.Code()] = bank
+}
+
+func (s *Switch) Bank(code string) (Bank, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.banks[code]
+	return b, ok
+}
+
+// Banks lists the members of the network sorted by code
+func (s *Switch) Banks() []BankInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	banks := make([]BankInfo, 0, len(s.banks))
+	for _, b := range s.banks {
+		banks = append(banks, BankInfo{Code: b.Code(), Name: b.Name()})
+	}
+	sort.Slice(banks, func(i, j int) bool {
+		return banks[i].Code < banks[j].Code
+	})
+	return banks
+}
+
+// Inquire asks the bank of bankCode for the name of the holder of acctNbr
+func (s *Switch) Inquire(ctx context.Context, bankCode, acctNbr string) (string, error) {
+	bank, ok := s.Bank(bankCode)
+	if !ok {
+		return "", ErrUnknownBank
+	}
+	// the answer is passed on the channel, it may come after the timeout when nobody reads it anymore
+	names := make(chan string, 1)
+	err := s.call(ctx, func(ctx context.Context) error {
+		name, err := bank.Inquire(ctx, acctNbr)
+		names <- name
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return <-names, nil
+}
+
+// Transfer sends req to the bank of req.ToBank
+func (s *Switch) Transfer(ctx context.Context, req Request) error {
+	bank, ok := s.Bank(req.ToBank)
+	if !ok {
+		return ErrUnknownBank
+	}
+	return s.call(ctx, func(ctx context.Context) error {
+		return bank.Credit(ctx, req)
+	})
+}
+
+// call runs f with the timeout of the switch. When the timeout is reached first, f is told
+// through its context and its answer is ignored.
+func (s *Switch) call(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,65 @@
+package interbank
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newNetwork() (*Switch, *SimulatedBank) {
+	sw := NewSwitch(50 * time.Millisecond)
+	bank := NewSimulatedBank("002", "Second Bank", Account{Number: "210001", Name: "Alice Smith", Balance: 500})
+	sw.Register(bank)
+	return sw, bank
+}
+
+func TestSwitch_RoutesByBankCode(t *testing.T) {
+	sw, bank := newNetwork()
+	ctx := context.Background()
+	assert.Equal(t, []BankInfo{{Code: "002", Name: "Second Bank"}}, sw.Banks())
+
+	name, err := sw.Inquire(ctx, "002", "210001")
+	assert.Nil(t, err)
+	assert.Equal(t, "Alice Smith", name)
+	_, err = sw.Inquire(ctx, "002", "999999")
+	assert.Equal(t, ErrAccountNotFound, err)
+	_, err = sw.Inquire(ctx, "009", "210001")
+	assert.Equal(t, ErrUnknownBank, err)
+
+	err = sw.Transfer(ctx, Request{ID: "TRX1", FromBank: "001", ToBank: "002", ToAccount: "210001", Amount: 25.5})
+	assert.Nil(t, err)
+	acc, _ := bank.Account("210001")
+	assert.Equal(t, 525.5, acc.Balance)
+	assert.Len(t, bank.Received(), 1)
+}
+
+func TestSwitch_RemoteFaults(t *testing.T) {
+	sw, bank := newNetwork()
+	ctx := context.Background()
+	req := Request{ID: "TRX1", FromBank: "001", ToBank: "002", ToAccount: "210001", Amount: 10}
+
+	bank.SetFault(FaultDecline)
+	assert.Equal(t, ErrDeclined, sw.Transfer(ctx, req))
+	bank.SetFault(FaultTimeout)
+	start := time.Now()
+	assert.Equal(t, ErrTimeout, sw.Transfer(ctx, req))
+	assert.Less(t, time.Since(start), time.Second)
+	_, err := sw.Inquire(ctx, "002", "210001")
+	assert.Equal(t, ErrTimeout, err)
+
+	acc, _ := bank.Account("210001")
+	assert.Equal(t, float64(500), acc.Balance)
+	assert.Empty(t, bank.Received())
+}
+
+func TestParseFault(t *testing.T) {
+	f, err := ParseFault("decline")
+	assert.Nil(t, err)
+	assert.Equal(t, FaultDecline, f)
+	f, _ = ParseFault("NONE")
+	assert.Equal(t, FaultNone, f)
+	_, err = ParseFault("JAM")
+	assert.Equal(t, ErrUnknownFault, err)
+}
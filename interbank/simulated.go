@@ -0,0 +1,146 @@
+package interbank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+)
+
+type Fault string
+
+const (
+	FaultNone Fault = ""
+	// FaultDecline makes the bank decline every transfer
+	FaultDecline Fault = "DECLINE"
+	// FaultTimeout makes the bank never answer
+	FaultTimeout Fault = "TIMEOUT"
+)
+
+var ErrUnknownFault = errors.New("unknown fault, use DECLINE, TIMEOUT or NONE")
+
+// ParseFault reads a fault name, NONE or an empty name clears the fault
+func ParseFault(name string) (Fault, error) {
+	switch f := Fault(strings.ToUpper(strings.TrimSpace(name))); f {
+	case FaultNone, FaultDecline, FaultTimeout:
+		return f, nil
+	case "NONE":
+		return FaultNone, nil
+	}
+	return FaultNone, ErrUnknownFault
+}
+
+// Account is an account of a simulated bank
+type Account struct {
+	Number  string  `json:"number"`
+	Name    string  `json:"name"`
+	Balance float64 `json:"balance"`
+	// Blocked accounts decline the transfers sent to them
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// SimulatedBank is a bank with its own accounts in memory that always answers unless a fault is set
+type SimulatedBank struct {
+	mu       sync.Mutex
+	code     string
+	name     string
+	accounts map[string]*Account
+	received []Request
+	fault    Fault
+}
+
+func NewSimulatedBank(code, name string, accounts ...Account) *SimulatedBank {
+	b := &SimulatedBank{code: code, name: name, accounts: make(map[string]*Account)}
+	for _, acc := range accounts {
+		acc := acc
+		b.accounts[acc.Number] = &acc
+	}
+	return b
+}
+
+func (b *SimulatedBank) Code() string {
+	return b.code
+}
+
+func (b *SimulatedBank) Name() string {
+	return b.name
+}
+
+func (b *SimulatedBank) SetFault(f Fault) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fault = f
+}
+
+func (b *SimulatedBank) Fault() Fault {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.fault
+}
+
+func (b *SimulatedBank) Inquire(ctx context.Context, acctNbr string) (string, error) {
+	if b.Fault() == FaultTimeout {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc := b.accounts[acctNbr]
+	if acc == nil {
+		return "", ErrAccountNotFound
+	} else if acc.Blocked {
+		return "", ErrDeclined
+	}
+	return acc.Name, nil
+}
+
+func (b *SimulatedBank) Credit(ctx context.Context, req Request) error {
+	switch b.Fault() {
+	case FaultTimeout:
+		<-ctx.Done()
+		return ctx.Err()
+	case FaultDecline:
+		return ErrDeclined
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc := b.accounts[req.ToAccount]
+	if acc == nil {
+		return ErrAccountNotFound
+	} else if acc.Blocked {
+		return ErrDeclined
+	}
+	acc.Balance = math.Round((acc.Balance+req.Amount)*100) / 100
+	b.received = append(b.received, req)
+	return nil
+}
+
+// Account gives a copy of an account of the bank
+func (b *SimulatedBank) Account(acctNbr string) (Account, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acc := b.accounts[acctNbr]
+	if acc == nil {
+		return Account{}, false
+	}
+	return *acc, true
+}
+
+// Received lists the transfers the bank was credited with
+func (b *SimulatedBank) Received() []Request {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Request(nil), b.received...)
+}
+
+// DefaultBanks are the simulated banks of the network when no other bank is configured
+func DefaultBanks() []*SimulatedBank {
+	return []*SimulatedBank{
+		NewSimulatedBank("002", "Second Bank",
+			Account{Number: "210001", Name: "Alice Smith", Balance: 500},
+			Account{Number: "210002", Name: "Bob Brown", Balance: 250}),
+		NewSimulatedBank("003", "Third Bank",
+			Account{Number: "310001", Name: "Carol White", Balance: 1000}),
+	}
+}
@@ -0,0 +1,138 @@
+package interest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// Rule is the interest paid on the accounts of one type : Rate percent a year, accrued every day.
+// With DAILY compounding the interest accrued but not posted yet earns interest too,
+// with MONTHLY compounding only the balance does. Accrued interest is posted to the account
+// every day or on the last day of the month, as Posting says. Balances below MinBalance earn nothing.
+type Rule struct {
+	AccountType string    `json:"accountType"`
+	Rate        float64   `json:"rate"`
+	Compounding Frequency `json:"compounding,omitempty"`
+	Posting     Frequency `json:"posting,omitempty"`
+	MinBalance  float64   `json:"minBalance,omitempty"`
+}
+
+// Accrue is the interest one day adds to accrued on balance
+func (r Rule) Accrue(balance, accrued float64) float64 {
+	if balance <= 0 || balance < r.MinBalance {
+		return 0
+	}
+	base := balance
+	if r.Compounding == Daily {
+		base += accrued
+	}
+	return base * r.Rate / 100 / 365
+}
+
+// PostingDay reports whether the interest accrued up to the end of day is posted to the account
+func (r Rule) PostingDay(day time.Time) bool {
+	if r.Posting == Daily {
+		return true
+	}
+	return day.AddDate(0, 0, 1).Day() == 1
+}
+
+// Engine keeps the interest rules, an engine without rules pays no interest
+type Engine struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+func New(rules ...Rule) (*Engine, error) {
+	e := &Engine{}
+	if err := e.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Load reads the rules from a JSON file, an empty path gives an engine without rules
+func Load(path string) (*Engine, error) {
+	if path == "" {
+		return New()
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling interest rules : %w", err)
+	}
+	return New(rules...)
+}
+
+// SetRules replaces all the rules, there can be one rule per account type.
+// Compounding defaults to DAILY and posting to MONTHLY.
+func (e *Engine) SetRules(rules []Rule) error {
+	byType := make(map[string]Rule, len(rules))
+	for _, r := range rules {
+		if r.Compounding == "" {
+			r.Compounding = Daily
+		}
+		if r.Posting == "" {
+			r.Posting = Monthly
+		}
+		if err := validate(r); err != nil {
+			return fmt.Errorf("%s interest : %w", r.AccountType, err)
+		} else if _, ok := byType[r.AccountType]; ok {
+			return fmt.Errorf("%s interest : more than one rule", r.AccountType)
+		}
+		byType[r.AccountType] = r
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = byType
+	return nil
+}
+
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	rules := make([]Rule, 0, len(e.rules))
+	for _, r := range e.rules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].AccountType < rules[j].AccountType
+	})
+	return rules
+}
+
+// Rule is the rule of accountType, if it earns interest
+func (e *Engine) Rule(accountType string) (Rule, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	r, ok := e.rules[accountType]
+	return r, ok
+}
+
+func validate(r Rule) error {
+	if r.AccountType == "" {
+		return errors.New("account type is required")
+	} else if r.Rate < 0 || r.MinBalance < 0 {
+		return errors.New("rate and minimum balance cannot be negative")
+	} else if r.Compounding != Daily && r.Compounding != Monthly {
+		return errors.New("compounding should be DAILY or MONTHLY")
+	} else if r.Posting != Daily && r.Posting != Monthly {
+		return errors.New("posting should be DAILY or MONTHLY")
+	}
+	return nil
+}
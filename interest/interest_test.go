@@ -0,0 +1,64 @@
+package interest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccrue_Compounding(t *testing.T) {
+	daily := Rule{AccountType: "SAVINGS", Rate: 36.5, Compounding: Daily}
+	assert.InDelta(t, 1.0, daily.Accrue(1000, 0), 1e-9)
+	assert.InDelta(t, 1.001, daily.Accrue(1000, 1), 1e-9)
+
+	monthly := Rule{AccountType: "SAVINGS", Rate: 36.5, Compounding: Monthly}
+	assert.InDelta(t, 1.0, monthly.Accrue(1000, 1), 1e-9)
+}
+
+func TestAccrue_MinBalance(t *testing.T) {
+	r := Rule{AccountType: "SAVINGS", Rate: 36.5, MinBalance: 100}
+	assert.Zero(t, r.Accrue(99, 0))
+	assert.Zero(t, r.Accrue(-50, 0))
+	assert.NotZero(t, r.Accrue(100, 0))
+}
+
+func TestPostingDay(t *testing.T) {
+	monthly := Rule{Posting: Monthly}
+	assert.False(t, monthly.PostingDay(time.Date(2026, 10, 30, 0, 0, 0, 0, time.Local)))
+	assert.True(t, monthly.PostingDay(time.Date(2026, 10, 31, 0, 0, 0, 0, time.Local)))
+	assert.True(t, monthly.PostingDay(time.Date(2028, 2, 29, 0, 0, 0, 0, time.Local)))
+	assert.True(t, Rule{Posting: Daily}.PostingDay(time.Date(2026, 10, 30, 0, 0, 0, 0, time.Local)))
+}
+
+func TestSetRules_DefaultsAndValidation(t *testing.T) {
+	e, err := New(Rule{AccountType: "SAVINGS", Rate: 2.5})
+	assert.Nil(t, err)
+	r, ok := e.Rule("SAVINGS")
+	assert.True(t, ok)
+	assert.Equal(t, Daily, r.Compounding)
+	assert.Equal(t, Monthly, r.Posting)
+	_, ok = e.Rule("CHECKING")
+	assert.False(t, ok)
+
+	_, err = New(Rule{AccountType: "SAVINGS", Rate: -1})
+	assert.NotNil(t, err)
+	_, err = New(Rule{AccountType: "SAVINGS", Posting: "WEEKLY"})
+	assert.NotNil(t, err)
+	_, err = New(Rule{AccountType: "SAVINGS"}, Rule{AccountType: "SAVINGS"})
+	assert.NotNil(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	e, err := Load("")
+	assert.Nil(t, err)
+	assert.Empty(t, e.Rules())
+
+	path := filepath.Join(t.TempDir(), "interest.json")
+	os.WriteFile(path, []byte(`[{"accountType": "SAVINGS", "rate": 2.5, "posting": "DAILY"}]`), 0o644)
+	e, err = Load(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []Rule{{AccountType: "SAVINGS", Rate: 2.5, Compounding: Daily, Posting: Daily}}, e.Rules())
+}
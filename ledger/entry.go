@@ -0,0 +1,130 @@
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+// OpeningEntry brings money onto a customer account that existed before the ledger
+func OpeningEntry(acctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Opening balance of %s", acctNbr),
+		Postings: []entity.Posting{
+			{Account: OpeningBalance, Amount: amount},
+			{Account: CustomerAccount(acctNbr), Amount: -amount},
+		},
+	}
+}
+
+// WithdrawEntry takes the money of a withdrawal out of the customer account and out of the ATM
+func WithdrawEntry(acctNbr, atmID string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Withdraw from %s at %s", acctNbr, atmID),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(acctNbr), Amount: amount},
+			{Account: ATMCashAccount(atmID), Amount: -amount},
+		},
+	}
+}
+
+func TransferEntry(fromAcctNbr, toAcctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Transfer from %s to %s", fromAcctNbr, toAcctNbr),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(fromAcctNbr), Amount: amount},
+			{Account: CustomerAccount(toAcctNbr), Amount: -amount},
+		},
+	}
+}
+
+// FXTransferEntry moves amount of fromCurrency out of fromAcctNbr and converted of toCurrency into toAcctNbr.
+// The bank buys the amount and sells the converted amount, through its position in each currency.
+func FXTransferEntry(fromAcctNbr, fromCurrency string, amount float64, toAcctNbr, toCurrency string, converted float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Transfer from %s to %s, %s to %s", fromAcctNbr, toAcctNbr, fromCurrency, toCurrency),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(fromAcctNbr), Amount: amount},
+			{Account: FXPositionAccount(fromCurrency), Amount: -amount},
+			{Account: FXPositionAccount(toCurrency), Amount: converted},
+			{Account: CustomerAccount(toAcctNbr), Amount: -converted},
+		},
+	}
+}
+
+// ReversalEntry undoes entry, it moves the money of every posting back
+func ReversalEntry(entry entity.JournalEntry) entity.JournalEntry {
+	reversal := entity.JournalEntry{
+		Description: "Reversal of " + entry.Description,
+		Postings:    make([]entity.Posting, len(entry.Postings)),
+	}
+	for i, p := range entry.Postings {
+		reversal.Postings[i] = entity.Posting{Account: p.Account, Amount: -p.Amount}
+	}
+	return reversal
+}
+
+func FeeEntry(acctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Fee charged to %s", acctNbr),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(acctNbr), Amount: amount},
+			{Account: FeeIncome, Amount: -amount},
+		},
+	}
+}
+
+// OverdraftInterestEntry charges the interest of a negative balance to the customer account
+func OverdraftInterestEntry(acctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Overdraft interest charged to %s", acctNbr),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(acctNbr), Amount: amount},
+			{Account: InterestIncome, Amount: -amount},
+		},
+	}
+}
+
+// InterestEntry pays the interest earned by a customer account
+func InterestEntry(acctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Interest paid to %s", acctNbr),
+		Postings: []entity.Posting{
+			{Account: InterestExpense, Amount: amount},
+			{Account: CustomerAccount(acctNbr), Amount: -amount},
+		},
+	}
+}
+
+// BillPaymentEntry moves the money of a bill from the customer account to what the bank owes the biller
+func BillPaymentEntry(acctNbr, billerCode string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Bill payment from %s to %s", acctNbr, billerCode),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(acctNbr), Amount: amount},
+			{Account: BillerAccount(billerCode), Amount: -amount},
+		},
+	}
+}
+
+// InterbankOutEntry moves the money of a transfer to another bank from the customer account to the settlement account of that bank
+func InterbankOutEntry(acctNbr, bankCode string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Interbank transfer from %s to bank %s", acctNbr, bankCode),
+		Postings: []entity.Posting{
+			{Account: CustomerAccount(acctNbr), Amount: amount},
+			{Account: SettlementAccount(bankCode), Amount: -amount},
+		},
+	}
+}
+
+// InterbankInEntry pays a transfer received from another bank into the customer account
+func InterbankInEntry(bankCode, acctNbr string, amount float64) entity.JournalEntry {
+	return entity.JournalEntry{
+		Description: fmt.Sprintf("Interbank transfer from bank %s to %s", bankCode, acctNbr),
+		Postings: []entity.Posting{
+			{Account: SettlementAccount(bankCode), Amount: amount},
+			{Account: CustomerAccount(acctNbr), Amount: -amount},
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+// Internal ledger accounts
+const (
+	Suspense        = "SUSPENSE"
+	FeeIncome       = "FEE_INCOME"
+	InterestIncome  = "INTEREST_INCOME"
+	InterestExpense = "INTEREST_EXPENSE"
+	OpeningBalance  = "OPENING_BALANCE"
+
+	customerPrefix   = "CUSTOMER:"
+	atmCashPrefix    = "ATM_CASH:"
+	billerPrefix     = "BILLER:"
+	settlementPrefix = "SETTLEMENT:"
+	fxPositionPrefix = "FX_POSITION:"
+)
+
+var ErrUnbalanced = errors.New("journal entry does not balance to zero")
+
+// CustomerAccount is the ledger account of a customer account number.
+// Customer accounts are liabilities of the bank, so their balance is a credit.
+func CustomerAccount(acctNbr string) string {
+	return customerPrefix + acctNbr
+}
+
+// ATMCashAccount is the ledger account of the cash dispensed by an ATM
+func ATMCashAccount(atmID string) string {
+	return atmCashPrefix + atmID
+}
+
+// BillerAccount is the ledger account of the money the bank owes a biller for the bills paid to it
+func BillerAccount(billerCode string) string {
+	return billerPrefix + billerCode
+}
+
+// SettlementAccount is the ledger account of what the bank owes another bank for the transfers
+// sent to it through the interbank switch, less the transfers received from it
+func SettlementAccount(bankCode string) string {
+	return settlementPrefix + bankCode
+}
+
+// FXPositionAccount is the ledger account of the bank's position in a currency, the money in that currency
+// customers sold to the bank, less what they bought from it, through transfers between currencies
+func FXPositionAccount(currency string) string {
+	return fxPositionPrefix + currency
+}
+
+// Ledger keeps the journal and the balance of every ledger account
+type Ledger struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	entries  []entity.JournalEntry
+	balances map[string]float64
+}
+
+// New opens an empty ledger, entries posted without a time are stamped with the time of c
+func New(c clock.Clock) *Ledger {
+	return &Ledger{clock: c, balances: make(map[string]float64)}
+}
+
+// Post adds a balanced journal entry, the entry gets an ID and a time when it has none
+func (l *Ledger) Post(entry entity.JournalEntry) (*entity.JournalEntry, error) {
+	if len(entry.Postings) < 2 {
+		return nil, errors.New("journal entry needs at least 2 postings")
+	}
+	postings := make([]entity.Posting, len(entry.Postings))
+	for i, p := range entry.Postings {
+		if p.Account == "" {
+			return nil, errors.New("posting account is required")
+		}
+		postings[i] = entity.Posting{Account: p.Account, Amount: roundCents(p.Amount)}
+	}
+	entry.Postings = postings
+	if !balanced(entry) {
+		return nil, ErrUnbalanced
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.ID == "" {
+		entry.ID = entity.FormatJournalEntryID(len(l.entries) + 1)
+	}
+	if entry.Time.IsZero() {
+		entry.Time = l.clock.Now()
+	}
+	l.entries = append(l.entries, entry)
+	for _, p := range entry.Postings {
+		l.balances[p.Account] = roundCents(l.balances[p.Account] + p.Amount)
+	}
+	return &entry, nil
+}
+
+// Balance is the debit balance of a ledger account, negative for a credit balance
+func (l *Ledger) Balance(account string) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.balances[account]
+}
+
+// CustomerBalance is the balance the customer sees on their account
+func (l *Ledger) CustomerBalance(acctNbr string) float64 {
+	b := l.Balance(CustomerAccount(acctNbr))
+	if b == 0 {
+		return 0
+	}
+	return -b
+}
+
+// CustomerBalanceAt is the balance the customer had on their account just before t
+func (l *Ledger) CustomerBalanceAt(acctNbr string, t time.Time) float64 {
+	account := CustomerAccount(acctNbr)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var b float64
+	for _, e := range l.entries {
+		if !e.Time.Before(t) {
+			continue
+		}
+		for _, p := range e.Postings {
+			if p.Account == account {
+				b += p.Amount
+			}
+		}
+	}
+	if b = roundCents(b); b == 0 {
+		return 0
+	}
+	return -b
+}
+
+// HasPostings reports whether anything has ever been posted to the account
+func (l *Ledger) HasPostings(account string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.balances[account]
+	return ok
+}
+
+func (l *Ledger) Entries() []entity.JournalEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]entity.JournalEntry(nil), l.entries...)
+}
+
+// Balances lists the balance of every ledger account
+func (l *Ledger) Balances() map[string]float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	balances := make(map[string]float64, len(l.balances))
+	for a, b := range l.balances {
+		balances[a] = b
+	}
+	return balances
+}
+
+// Check verifies the ledger invariants : every journal entry balances to zero
+// and the account balances are the sum of their postings
+func (l *Ledger) Check() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var violations []string
+	sums := make(map[string]float64)
+	for _, e := range l.entries {
+		if !balanced(e) {
+			violations = append(violations, fmt.Sprintf("%s does not balance to zero", e.ID))
+		}
+		for _, p := range e.Postings {
+			sums[p.Account] = roundCents(sums[p.Account] + p.Amount)
+		}
+	}
+	accounts := make([]string, 0, len(l.balances))
+	for a := range l.balances {
+		accounts = append(accounts, a)
+	}
+	sort.Strings(accounts)
+	for _, a := range accounts {
+		if sums[a] != l.balances[a] {
+			violations = append(violations, fmt.Sprintf("%s balance %.2f is not the sum of its postings %.2f", a, l.balances[a], sums[a]))
+		}
+	}
+	return violations
+}
+
+// CustomerAccountNumber returns the account number of a customer ledger account
+func CustomerAccountNumber(account string) (string, bool) {
+	if !strings.HasPrefix(account, customerPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(account, customerPrefix), true
+}
+
+func balanced(e entity.JournalEntry) bool {
+	var sum float64
+	for _, p := range e.Postings {
+		sum += p.Amount
+	}
+	return math.Abs(sum) < 0.005
+}
+
+func roundCents(a float64) float64 {
+	return math.Round(a*100) / 100
+}
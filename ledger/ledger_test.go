@@ -0,0 +1,80 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPost_RejectsUnbalancedEntry(t *testing.T) {
+	l := New(clock.Real())
+	_, err := l.Post(entity.JournalEntry{Postings: []entity.Posting{
+		{Account: CustomerAccount("112233"), Amount: 10},
+		{Account: ATMCashAccount("ATM001"), Amount: -9.99},
+	}})
+	assert.Equal(t, ErrUnbalanced, err)
+	assert.Empty(t, l.Entries())
+	assert.False(t, l.HasPostings(CustomerAccount("112233")))
+}
+
+func TestPost_SingleLegEntry(t *testing.T) {
+	l := New(clock.Real())
+	_, err := l.Post(entity.JournalEntry{Postings: []entity.Posting{{Account: Suspense, Amount: 0}}})
+	assert.NotNil(t, err)
+}
+
+func TestPost_DerivesBalances(t *testing.T) {
+	l := New(clock.Real())
+	l.Post(OpeningEntry("112233", 100))
+	l.Post(OpeningEntry("112244", 100))
+	l.Post(WithdrawEntry("112233", "ATM001", 30))
+	posted, err := l.Post(TransferEntry("112233", "112244", 20.5))
+	assert.Nil(t, err)
+	assert.Equal(t, "JE00000004", posted.ID)
+	assert.False(t, posted.Time.IsZero())
+
+	assert.Equal(t, 49.5, l.CustomerBalance("112233"))
+	assert.Equal(t, 120.5, l.CustomerBalance("112244"))
+	assert.Equal(t, -30.0, l.Balance(ATMCashAccount("ATM001")))
+
+	var total float64
+	for _, b := range l.Balances() {
+		total += b
+	}
+	assert.Equal(t, 0.0, total)
+	assert.Empty(t, l.Check())
+}
+
+func TestPost_RoundsToCents(t *testing.T) {
+	l := New(clock.Real())
+	for i := 0; i < 10; i++ {
+		_, err := l.Post(TransferEntry("112233", "112244", 0.1))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1.0, l.CustomerBalance("112244"))
+	assert.Equal(t, -1.0, l.CustomerBalance("112233"))
+}
+
+func TestCustomerBalanceAt(t *testing.T) {
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	c := clock.NewFake(monday)
+	l := New(c)
+	l.Post(OpeningEntry("112233", 100))
+	c.Advance(24 * time.Hour)
+	l.Post(WithdrawEntry("112233", "ATM001", 30))
+
+	assert.Equal(t, 0.0, l.CustomerBalanceAt("112233", monday))
+	assert.Equal(t, 100.0, l.CustomerBalanceAt("112233", monday.Add(time.Hour)))
+	assert.Equal(t, 70.0, l.CustomerBalanceAt("112233", c.Now().Add(time.Hour)))
+}
+
+func TestCustomerAccountNumber(t *testing.T) {
+	acctNbr, ok := CustomerAccountNumber(CustomerAccount("112233"))
+	assert.True(t, ok)
+	assert.Equal(t, "112233", acctNbr)
+	_, ok = CustomerAccountNumber(ATMCashAccount("ATM001"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,97 @@
+package accountCsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+// Header is the column order of the account CSV file
+var Header = []string{"Name", "PIN", "Balance", "Account Number"}
+
+// Record is one line of the account CSV file, Err is set when the line cannot be read as an account
+type Record struct {
+	Line    int
+	Raw     string
+	Account entity.Account
+	Err     error
+}
+
+// Read reads every line of the file, a first line equal to Header is skipped
+func Read(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var records []Record
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			records = append(records, Record{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(records) == 0 && isHeader(fields) {
+			continue
+		}
+		records = append(records, parse(line, fields))
+	}
+	return records, nil
+}
+
+func Write(w io.Writer, accounts []entity.Account) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Header); err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		err := cw.Write([]string{a.Name, a.PIN, strconv.FormatFloat(a.Balance, 'f', -1, 64), a.AccountNumber})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func parse(line int, fields []string) Record {
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	rec := Record{Line: line, Raw: strings.Join(fields, ",")}
+	if len(fields) != len(Header) {
+		rec.Err = fmt.Errorf("Expected %d fields (%s), got %d", len(Header), strings.Join(Header, ","), len(fields))
+		return rec
+	}
+	rec.Account = entity.Account{Name: fields[0], PIN: fields[1], AccountNumber: fields[3]}
+	balance, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || math.IsNaN(balance) || math.IsInf(balance, 0) {
+		rec.Err = fmt.Errorf("Invalid balance %q", fields[2])
+		return rec
+	}
+	rec.Account.Balance = balance
+	return rec
+}
+
+func isHeader(fields []string) bool {
+	if len(fields) != len(Header) {
+		return false
+	}
+	for i := range fields {
+		if !strings.EqualFold(strings.TrimSpace(fields[i]), Header[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package luhn
+
+// Valid reports whether number is a string of digits whose last digit is its Luhn check digit
+func Valid(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	sum, ok := sum(number[:len(number)-1])
+	if !ok {
+		return false
+	}
+	last := number[len(number)-1]
+	return last >= '0' && last <= '9' && int(last-'0') == (10-sum%10)%10
+}
+
+// Append adds the Luhn check digit to a string of digits
+func Append(payload string) string {
+	sum, _ := sum(payload)
+	return payload + string(rune('0'+(10-sum%10)%10))
+}
+
+// sum is the Luhn sum of payload, doubling every other digit starting from the rightmost one
+func sum(payload string) (int, bool) {
+	total := 0
+	for i := 0; i < len(payload); i++ {
+		c := payload[len(payload)-1-i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := int(c - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		total += d
+	}
+	return total, true
+}
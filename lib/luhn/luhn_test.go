@@ -0,0 +1,21 @@
+package luhn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid("4111111111111111"))
+	assert.True(t, Valid("79927398713"))
+	assert.False(t, Valid("4111111111111112"))
+	assert.False(t, Valid("4111a11111111111"))
+	assert.False(t, Valid("7"))
+}
+
+func TestAppend(t *testing.T) {
+	assert.Equal(t, "79927398713", Append("7992739871"))
+	assert.Equal(t, "4000000000000010", Append("400000000000001"))
+	assert.True(t, Valid(Append("400000000000123")))
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait page, in points
+const (
+	PageWidth  = 595
+	PageHeight = 842
+
+	margin   = 40
+	fontSize = 9
+	leading  = 12
+)
+
+// LinesPerPage is the number of text lines that fit in one page
+const LinesPerPage = (PageHeight - 2*margin) / leading
+
+// Document is a text only PDF written with the built-in Courier font,
+// so that columns line up without embedding any font.
+type Document struct {
+	pages [][]string
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddLines writes lines to the document, starting a new page when the current one is full
+func (d *Document) AddLines(lines ...string) {
+	for _, l := range lines {
+		if len(d.pages) == 0 || len(d.pages[len(d.pages)-1]) == LinesPerPage {
+			d.pages = append(d.pages, nil)
+		}
+		d.pages[len(d.pages)-1] = append(d.pages[len(d.pages)-1], l)
+	}
+}
+
+// NewPage makes the next line start on a new page
+func (d *Document) NewPage() {
+	d.pages = append(d.pages, nil)
+}
+
+func (d *Document) Bytes() []byte {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = [][]string{nil}
+	}
+	// object 1 is the catalog, 2 the page tree, 3 the font,
+	// then every page takes 2 objects : the page and its content stream
+	var objects []string
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, lines := range pages {
+		content := pageContent(lines)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				PageWidth, PageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+func pageContent(lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, PageHeight-margin)
+	for _, l := range lines {
+		fmt.Fprintf(&b, "(%s) '\n", escape(l))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", "", "\n", "")
+	return r.Replace(s)
+}
@@ -0,0 +1,29 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes_XrefPointsToObjects(t *testing.T) {
+	doc := New()
+	for i := 0; i < LinesPerPage+1; i++ {
+		doc.AddLines(fmt.Sprintf("line (%d)", i))
+	}
+	b := doc.Bytes()
+	assert.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4\n")))
+	assert.Contains(t, string(b), "/Count 2")
+	assert.Contains(t, string(b), `(line \(0\)) '`)
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(b, -1)
+	assert.Len(t, offsets, 7)
+	for i, o := range offsets {
+		off, _ := strconv.Atoi(string(o[1]))
+		assert.True(t, bytes.HasPrefix(b[off:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+	}
+}
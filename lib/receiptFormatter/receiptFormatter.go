@@ -0,0 +1,111 @@
+package receiptFormatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+)
+
+// Width is the number of columns of the receipt printer
+const Width = 40
+
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	escFeed4       = []byte{0x1b, 'd', 4}
+	gsPartialCut   = []byte{0x1d, 'V', 1}
+)
+
+const title = "ATM SIMULATION"
+const footer = "THANK YOU FOR USING OUR ATM"
+
+// Text formats the receipt as plain text lines of Width columns
+func Text(r *entity.Receipt) string {
+	var b strings.Builder
+	separator := strings.Repeat("=", Width)
+	b.WriteString(separator + "\n")
+	b.WriteString(center(title) + "\n")
+	b.WriteString(separator + "\n")
+	for _, l := range body(r) {
+		b.WriteString(l + "\n")
+	}
+	b.WriteString(separator + "\n")
+	b.WriteString(center(footer) + "\n")
+	return b.String()
+}
+
+// ESCPOS formats the receipt as a byte stream for ESC/POS receipt printers
+func ESCPOS(r *entity.Receipt) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+	b.Write(escAlignCenter)
+	b.Write(escBoldOn)
+	b.WriteString(title + "\n")
+	b.Write(escBoldOff)
+	b.Write(escAlignLeft)
+	b.WriteString(strings.Repeat("-", Width) + "\n")
+	for _, l := range body(r) {
+		b.WriteString(l + "\n")
+	}
+	b.WriteString(strings.Repeat("-", Width) + "\n")
+	b.Write(escAlignCenter)
+	b.WriteString(footer + "\n")
+	b.Write(escFeed4)
+	b.Write(gsPartialCut)
+	return b.Bytes()
+}
+
+func body(r *entity.Receipt) []string {
+	lines := []string{
+		line("ATM ID", r.ATMID),
+		line("DATE", r.Time.Format("2006-01-02")),
+		line("TIME", r.Time.Format("15:04:05")),
+		line("ACCOUNT", r.AccountNumber),
+		strings.Repeat("-", Width),
+		line("TRANSACTION", strings.ReplaceAll(string(r.Type), "_", " ")),
+	}
+	if r.DestinationAccountNumber != "" {
+		lines = append(lines, line("DESTINATION", r.DestinationAccountNumber))
+	}
+	if r.DestinationBank != "" {
+		lines = append(lines, line("BANK", r.DestinationBank))
+	}
+	if r.Biller != "" {
+		lines = append(lines, line("BILLER", r.Biller), line("BILLER REF", r.BillerReference))
+	}
+	lines = append(lines, line("AMOUNT", fx.Format(r.Currency, r.Amount)))
+	if r.FXRate != 0 {
+		lines = append(lines,
+			line("RATE", fmt.Sprintf("1 %s = %g %s", r.Currency, r.FXRate, r.ConvertedCurrency)),
+			line("CREDITED", fx.Format(r.ConvertedCurrency, r.ConvertedAmount)))
+	}
+	if r.Fee > 0 {
+		lines = append(lines, line("FEE", fx.Format(r.Currency, r.Fee)))
+	}
+	return append(lines,
+		line("REFERENCE", r.Reference),
+		line("BALANCE", fx.Format(r.Currency, r.Balance)),
+	)
+}
+
+// line puts label on the left and value on the right of a Width columns line
+func line(label, value string) string {
+	space := Width - len(label) - len(value)
+	if space < 1 {
+		return (label + " " + value)[:Width]
+	}
+	return label + strings.Repeat(" ", space) + value
+}
+
+func center(s string) string {
+	if len(s) >= Width {
+		return s[:Width]
+	}
+	return strings.Repeat(" ", (Width-len(s))/2) + s
+}
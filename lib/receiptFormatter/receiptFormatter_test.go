@@ -0,0 +1,57 @@
+package receiptFormatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var receipt = &entity.Receipt{
+	TransactionID: "TRX00000001",
+	ATMID:         "ATM001",
+	AccountNumber: "****33",
+	Time:          time.Date(2026, 10, 19, 17, 4, 31, 0, time.UTC),
+	Type:          entity.TransactionWithdraw,
+	Amount:        50,
+	Reference:     "TRX00000001",
+	Balance:       50,
+}
+
+func TestText_FitsPrinterWidth(t *testing.T) {
+	text := Text(receipt)
+	for _, l := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, len(l), Width)
+	}
+	assert.Contains(t, text, "AMOUNT                            $50.00")
+	assert.Contains(t, text, "DATE                          2026-10-19")
+}
+
+func TestESCPOS_InitializesAndCuts(t *testing.T) {
+	b := ESCPOS(receipt)
+	assert.True(t, bytes.HasPrefix(b, escInit))
+	assert.True(t, bytes.HasSuffix(b, gsPartialCut))
+	assert.True(t, bytes.Contains(b, []byte("BALANCE                           $50.00\n")))
+}
+
+func TestText_FeeLineOnlyWhenCharged(t *testing.T) {
+	assert.NotContains(t, Text(receipt), "FEE")
+	withFee := *receipt
+	withFee.Fee = 2.5
+	assert.Contains(t, Text(&withFee), "FEE                                $2.50")
+}
+
+func TestText_FXTransfer(t *testing.T) {
+	transfer := *receipt
+	transfer.Type = entity.TransactionTransferOut
+	transfer.Currency = "USD"
+	transfer.FXRate = 0.92
+	transfer.ConvertedAmount = 46
+	transfer.ConvertedCurrency = "EUR"
+	text := Text(&transfer)
+	assert.Contains(t, text, "RATE                    1 USD = 0.92 EUR")
+	assert.Contains(t, text, "CREDITED                       EUR 46.00")
+}
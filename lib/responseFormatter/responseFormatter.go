@@ -9,12 +9,19 @@ type ResponseFormatter struct {
 	StatusCode int
 	Message    string
 	IsError    bool
+	Code       string `json:",omitempty"`
 }
 
 func New(statusCode int, message string, isError bool) *ResponseFormatter {
 	return &ResponseFormatter{StatusCode: statusCode, Message: message, IsError: isError}
 }
 
+// WithCode sets an error code clients can react to without parsing the message
+func (r *ResponseFormatter) WithCode(code string) *ResponseFormatter {
+	r.Code = code
+	return r
+}
+
 func (r *ResponseFormatter) ReturnAsJson(w http.ResponseWriter) {
 	w.Header().Add("content-type", "application/json")
 	if r == nil {
@@ -0,0 +1,86 @@
+package statementFormatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/pdf"
+)
+
+const (
+	dateFormat     = "2006-01-02"
+	dateTimeFormat = "2006-01-02 15:04"
+)
+
+// CSV writes one row per transaction between an opening balance row and the total and closing balance rows
+func CSV(st *entity.Statement) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	rows := [][]string{
+		{"Date", "Transaction ID", "Type", "Description", "Debit", "Credit", "Balance"},
+		{st.From.Format(dateTimeFormat), "", "", "Opening balance", "", "", amount(st.OpeningBalance)},
+	}
+	for _, l := range st.Lines {
+		rows = append(rows, []string{
+			l.Time.Format(dateTimeFormat), l.TransactionID, string(l.Type), l.Description,
+			optionalAmount(l.Debit), optionalAmount(l.Credit), amount(l.Balance),
+		})
+	}
+	rows = append(rows,
+		[]string{st.To.Format(dateTimeFormat), "", "", "Total", amount(st.TotalDebit), amount(st.TotalCredit), ""},
+		[]string{st.To.Format(dateTimeFormat), "", "", "Closing balance", "", "", amount(st.ClosingBalance)},
+	)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func PDF(st *entity.Statement) []byte {
+	doc := pdf.New()
+	header := fmt.Sprintf("%-16s  %-11s  %-30s  %10s  %10s  %10s",
+		"DATE", "TRX ID", "DESCRIPTION", "DEBIT", "CREDIT", "BALANCE")
+	separator := strings.Repeat("-", len(header))
+	doc.AddLines(
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Name            : %s", st.Name),
+		fmt.Sprintf("Account Number  : %s", st.AccountNumber),
+		fmt.Sprintf("Period          : %s - %s", st.From.Format(dateFormat), st.To.Format(dateFormat)),
+		fmt.Sprintf("Opening Balance : %s", fx.Format(st.Currency, st.OpeningBalance)),
+		"",
+		header,
+		separator,
+	)
+	for _, l := range st.Lines {
+		desc := l.Description
+		if len(desc) > 30 {
+			desc = desc[:30]
+		}
+		doc.AddLines(fmt.Sprintf("%-16s  %-11s  %-30s  %10s  %10s  %10s",
+			l.Time.Format(dateTimeFormat), l.TransactionID, desc,
+			optionalAmount(l.Debit), optionalAmount(l.Credit), amount(l.Balance)))
+	}
+	doc.AddLines(
+		separator,
+		fmt.Sprintf("%-61s  %10s  %10s", "TOTAL", amount(st.TotalDebit), amount(st.TotalCredit)),
+		"",
+		fmt.Sprintf("Closing Balance : %s", fx.Format(st.Currency, st.ClosingBalance)),
+	)
+	return doc.Bytes()
+}
+
+func amount(a float64) string {
+	return fmt.Sprintf("%.2f", a)
+}
+
+func optionalAmount(a float64) string {
+	if a == 0 {
+		return ""
+	}
+	return amount(a)
+}
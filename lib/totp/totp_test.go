@@ -0,0 +1,62 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 secret of the RFC 6238 test vectors
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+	// the RFC vectors have 8 digits, a 6 digit code is their last 6 digits
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		code, err := Code(rfcSecret, Counter(time.Unix(unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, want, code, unix)
+	}
+	_, err := Code("not base32!", 1)
+	assert.NotNil(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, Counter(now))
+	counter, ok := Verify(rfcSecret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, Counter(now), counter)
+
+	_, ok = Verify(rfcSecret, code, now.Add(Period), 0)
+	assert.True(t, ok, "one period of drift is allowed")
+	_, ok = Verify(rfcSecret, code, now.Add(2*Period), 0)
+	assert.False(t, ok)
+	_, ok = Verify(rfcSecret, code, now, counter)
+	assert.False(t, ok, "a code cannot be used twice")
+	_, ok = Verify(rfcSecret, "000000", now, 0)
+	assert.False(t, ok)
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, err := NewSecret()
+	assert.Nil(t, err)
+	assert.Len(t, secret, 32)
+	_, err = Code(secret, 1)
+	assert.Nil(t, err)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	assert.Equal(t,
+		"otpauth://totp/ATM%20Bank:112233?algorithm=SHA1&digits=6&issuer=ATM+Bank&period=30&secret=JBSWY3DPEHPK3PXP",
+		ProvisioningURI("ATM Bank", "112233", "JBSWY3DPEHPK3PXP"))
+}
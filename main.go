@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/fazarmitrais/atm-simulation/clock"
 	"github.com/fazarmitrais/atm-simulation/delivery/rest"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/repository"
 	"github.com/fazarmitrais/atm-simulation/service"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -13,7 +18,17 @@ import (
 
 func main() {
 	envInit()
-	svc := service.New()
+	repo, err := repository.Open(envLib.GetEnv("REPOSITORY"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts := []service.Option{service.WithRepository(repo)}
+	if envLib.GetEnv("ATM_TEST_MODE") == "true" {
+		// lets QA move time forward from the admin API
+		opts = append(opts, service.WithClock(clock.NewOffset(clock.Real())))
+	}
+	svc := service.New(opts...)
+	go svc.StartJobs(context.Background(), time.Minute)
 	re := rest.New(svc)
 	m := mux.NewRouter()
 	re.Register(m)
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// Admin only lets through requests with the X-Admin-Key header matching ADMIN_API_KEY.
+// The admin API is disabled when ADMIN_API_KEY is not set.
+func Admin() Middleware {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := envLib.GetEnv("ADMIN_API_KEY")
+			if key == "" {
+				responseFormatter.New(http.StatusForbidden, "Admin API is disabled", true).ReturnAsJson(w)
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Key")), []byte(key)) != 1 {
+				responseFormatter.New(http.StatusUnauthorized, "Invalid admin key", true).ReturnAsJson(w)
+				return
+			}
+			f(w, r)
+		}
+	}
+}
@@ -2,15 +2,20 @@ package middleware
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/fazarmitrais/atm-simulation/clock"
 	"github.com/fazarmitrais/atm-simulation/cookie"
 	"github.com/fazarmitrais/atm-simulation/lib/envLib"
 	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/screen"
 )
 
 type Middleware func(http.HandlerFunc) http.HandlerFunc
 
-func Required(cookie *cookie.Cookie) Middleware {
+// Required rejects the request when the session is not logged in, or when it has been idle
+// for longer than SESSION_TIMEOUT according to clk. Sessions never expire when SESSION_TIMEOUT is not set.
+func Required(cookie *cookie.Cookie, clk clock.Clock) Middleware {
 	return func(f http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			session, err := cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
@@ -18,15 +23,64 @@ func Required(cookie *cookie.Cookie) Middleware {
 				responseFormatter.New(http.StatusInternalServerError, "Failed to get cookies", true).ReturnAsJson(w)
 				return
 			}
-			if !session.Values["authenticated"].(bool) || session.Values["acctNbr"] == nil {
+			authenticated, _ := session.Values["authenticated"].(bool)
+			if !authenticated || session.Values["acctNbr"] == nil {
 				responseFormatter.New(http.StatusForbidden, "Please login first", true).ReturnAsJson(w)
 				return
 			}
+			now := clk.Now()
+			if timeout, err := time.ParseDuration(envLib.GetEnv("SESSION_TIMEOUT")); err == nil && timeout > 0 {
+				lastSeen, _ := session.Values["lastSeen"].(int64)
+				if now.Sub(time.Unix(lastSeen, 0)) > timeout {
+					session.Values["authenticated"] = false
+					session.Values["acctNbr"] = nil
+					session.Save(r, w)
+					responseFormatter.New(http.StatusForbidden, "Session expired, please login again", true).ReturnAsJson(w)
+					return
+				}
+			}
+			session.Values["lastSeen"] = now.Unix()
+			session.Save(r, w)
 			f(w, r)
 		}
 	}
 }
 
+// Screen rejects the request when event is not permitted on the session's current screen,
+// and moves the session to the next screen when the request succeeds
+func Screen(cookie *cookie.Cookie, machine *screen.Machine, event screen.Event) Middleware {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			session, err := cookie.Store.Get(r, envLib.GetEnv("COOKIE_STORE_NAME"))
+			if err != nil {
+				responseFormatter.New(http.StatusInternalServerError, "Failed to get cookies", true).ReturnAsJson(w)
+				return
+			}
+			sessionID, _ := session.Values["sessionID"].(string)
+			if !machine.Can(sessionID, event) {
+				responseFormatter.New(http.StatusConflict,
+					"Operation is not allowed on the current screen", true).ReturnAsJson(w)
+				return
+			}
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			f(sw, r)
+			if sw.status >= http.StatusOK && sw.status < http.StatusMultipleChoices {
+				machine.Fire(sessionID, event)
+			}
+		}
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
 func Chain(f http.HandlerFunc, middleWares ...Middleware) http.HandlerFunc {
 	for _, m := range middleWares {
 		f = m(f)
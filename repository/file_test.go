@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile_SaveAndLoad(t *testing.T) {
+	f := NewFile(filepath.Join(t.TempDir(), "data", "atm.json"))
+	snapshot, err := f.Load()
+	assert.Nil(t, err)
+	assert.Nil(t, snapshot)
+
+	saved := &Snapshot{
+		Accounts:     []entity.Account{{Name: "John Doe", AccountNumber: "112233", PIN: "012108", Balance: 100}},
+		Transactions: []entity.Transaction{{ID: "TRX00000001", AccountNumber: "112233", Amount: 10}},
+	}
+	assert.Nil(t, f.Save(saved))
+	snapshot, err = f.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, saved, snapshot)
+}
+
+func TestOpen(t *testing.T) {
+	r, err := Open("")
+	assert.Nil(t, err)
+	assert.IsType(t, &Memory{}, r)
+	r, err = Open("file:atm.json")
+	assert.Nil(t, err)
+	assert.IsType(t, &File{}, r)
+	_, err = Open("postgres://localhost")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+type Memory struct {
+	mu       sync.Mutex
+	snapshot *Snapshot
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Load() (*Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot.copy(), nil
+}
+
+func (m *Memory) Save(snapshot *Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = snapshot.copy()
+	return nil
+}
+
+// copy keeps the stored snapshot away from later changes made by the caller
+func (s *Snapshot) copy() *Snapshot {
+	if s == nil {
+		return nil
+	}
+	return &Snapshot{
+		Accounts:            append([]entity.Account(nil), s.Accounts...),
+		Transactions:        append([]entity.Transaction(nil), s.Transactions...),
+		Journal:             append([]entity.JournalEntry(nil), s.Journal...),
+		Disputes:            append([]entity.Dispute(nil), s.Disputes...),
+		Holds:               append([]entity.Hold(nil), s.Holds...),
+		Cards:               append([]entity.Card(nil), s.Cards...),
+		JobRuns:             copyJobRuns(s.JobRuns),
+		StandingOrders:      append([]entity.StandingOrder(nil), s.StandingOrders...),
+		Notifications:       append([]entity.Notification(nil), s.Notifications...),
+		Beneficiaries:       append([]entity.Beneficiary(nil), s.Beneficiaries...),
+		CardlessWithdrawals: append([]entity.CardlessWithdrawal(nil), s.CardlessWithdrawals...),
+	}
+}
+
+func copyJobRuns(jobRuns map[string]string) map[string]string {
+	if jobRuns == nil {
+		return nil
+	}
+	c := make(map[string]string, len(jobRuns))
+	for job, day := range jobRuns {
+		c[job] = day
+	}
+	return c
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+// Snapshot is the state of the ATM network kept between restarts
+type Snapshot struct {
+	Accounts     []entity.Account      `json:"accounts"`
+	Transactions []entity.Transaction  `json:"transactions"`
+	Journal      []entity.JournalEntry `json:"journal"`
+	Disputes     []entity.Dispute      `json:"disputes"`
+	Holds        []entity.Hold         `json:"holds"`
+	Cards        []entity.Card         `json:"cards"`
+	// JobRuns is the last day each batch job ran for
+	JobRuns        map[string]string      `json:"jobRuns,omitempty"`
+	StandingOrders []entity.StandingOrder `json:"standingOrders"`
+	Notifications  []entity.Notification  `json:"notifications"`
+	Beneficiaries  []entity.Beneficiary   `json:"beneficiaries"`
+	// CardlessWithdrawals keep their hold across a restart, so they are saved with it
+	CardlessWithdrawals []entity.CardlessWithdrawal `json:"cardlessWithdrawals"`
+}
+
+// Repository stores the whole snapshot at once.
+// Load returns a nil snapshot when nothing has been saved yet.
+type Repository interface {
+	Load() (*Snapshot, error)
+	Save(snapshot *Snapshot) error
+}
+
+// Open creates the repository backend described by dsn :
+// "memory" (or empty) keeps the data in memory only, "file:<path>" keeps it in a JSON file
+func Open(dsn string) (Repository, error) {
+	switch {
+	case dsn == "" || dsn == "memory":
+		return NewMemory(), nil
+	case strings.HasPrefix(dsn, "file:"):
+		path := strings.TrimPrefix(dsn, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("file repository needs a path, e.g. file:data/atm.json")
+		}
+		return NewFile(path), nil
+	}
+	return nil, fmt.Errorf("unknown repository %q, use memory or file:<path>", dsn)
+}
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+)
+
+// DateFormat is the format of the days jobs run for
+const DateFormat = "2006-01-02"
+
+// Job is a daily batch job. Run does the work of one day, it should do nothing
+// when the work of that day is already done so that a job can safely run twice.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context, day time.Time) error
+}
+
+// Run is the outcome of a job for one day
+type Run struct {
+	Job   string `json:"job"`
+	Day   string `json:"day"`
+	Error string `json:"error,omitempty"`
+}
+
+// Status is a job and the last day it ran for
+type Status struct {
+	Job     string `json:"job"`
+	LastDay string `json:"lastDay,omitempty"`
+}
+
+// Scheduler runs every job once for every day, catching up the days it missed
+// while the app was down. A failed day is retried on the next run.
+type Scheduler struct {
+	// running keeps two RunDue calls from running the same day twice,
+	// mu guards the fields and is not held while a job runs
+	running  sync.Mutex
+	mu       sync.Mutex
+	clock    clock.Clock
+	jobs     []Job
+	lastDays map[string]string
+}
+
+func New(c clock.Clock) *Scheduler {
+	return &Scheduler{clock: c, lastDays: make(map[string]string)}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+// Restore sets the last day each job ran for, as saved by LastDays
+func (s *Scheduler) Restore(lastDays map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastDays = make(map[string]string, len(lastDays))
+	for job, day := range lastDays {
+		s.lastDays[job] = day
+	}
+}
+
+func (s *Scheduler) LastDays() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lastDays := make(map[string]string, len(s.lastDays))
+	for job, day := range s.lastDays {
+		lastDays[job] = day
+	}
+	return lastDays
+}
+
+func (s *Scheduler) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := make([]Status, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		status = append(status, Status{Job: job.Name, LastDay: s.lastDays[job.Name]})
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Job < status[j].Job
+	})
+	return status
+}
+
+// RunDue runs every job for the days from the day after its last run up to today.
+// A job that never ran only runs for today.
+func (s *Scheduler) RunDue(ctx context.Context) []Run {
+	s.running.Lock()
+	defer s.running.Unlock()
+	s.mu.Lock()
+	now := s.clock.Now()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	runs := []Run{}
+	for _, job := range jobs {
+		day := today
+		if last, err := time.ParseInLocation(DateFormat, s.lastDay(job.Name), now.Location()); err == nil {
+			day = last.AddDate(0, 0, 1)
+		}
+		for ; !day.After(today); day = day.AddDate(0, 0, 1) {
+			run := Run{Job: job.Name, Day: day.Format(DateFormat)}
+			if err := job.Run(ctx, day); err != nil {
+				run.Error = err.Error()
+				runs = append(runs, run)
+				break
+			}
+			s.mu.Lock()
+			s.lastDays[job.Name] = run.Day
+			s.mu.Unlock()
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+func (s *Scheduler) lastDay(job string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastDays[job]
+}
+
+// Start calls RunDue every interval until ctx is done, after is called with the runs of every call
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration, after func([]Run)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		after(s.RunDue(ctx))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
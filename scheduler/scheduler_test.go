@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDue_RunsOncePerDay(t *testing.T) {
+	c := clock.NewFake(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local))
+	s := New(c)
+	var days []string
+	s.Add(Job{Name: "count", Run: func(ctx context.Context, day time.Time) error {
+		days = append(days, day.Format(DateFormat))
+		return nil
+	}})
+
+	assert.Equal(t, []Run{{Job: "count", Day: "2026-10-19"}}, s.RunDue(context.Background()))
+	c.Advance(time.Hour)
+	assert.Empty(t, s.RunDue(context.Background()))
+	c.Advance(24 * time.Hour)
+	s.RunDue(context.Background())
+	assert.Equal(t, []string{"2026-10-19", "2026-10-20"}, days)
+	assert.Equal(t, []Status{{Job: "count", LastDay: "2026-10-20"}}, s.Status())
+}
+
+func TestRunDue_CatchesUpAfterRestore(t *testing.T) {
+	c := clock.NewFake(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local))
+	s := New(c)
+	var days []string
+	s.Add(Job{Name: "count", Run: func(ctx context.Context, day time.Time) error {
+		days = append(days, day.Format(DateFormat))
+		return nil
+	}})
+	s.Restore(map[string]string{"count": "2026-10-16"})
+	s.RunDue(context.Background())
+	assert.Equal(t, []string{"2026-10-17", "2026-10-18", "2026-10-19"}, days)
+	assert.Equal(t, map[string]string{"count": "2026-10-19"}, s.LastDays())
+}
+
+func TestRunDue_RetriesFailedDay(t *testing.T) {
+	c := clock.NewFake(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local))
+	s := New(c)
+	fail := true
+	s.Add(Job{Name: "flaky", Run: func(ctx context.Context, day time.Time) error {
+		if fail {
+			return errors.New("database is down")
+		}
+		return nil
+	}})
+	s.Restore(map[string]string{"flaky": "2026-10-17"})
+	runs := s.RunDue(context.Background())
+	assert.Equal(t, []Run{{Job: "flaky", Day: "2026-10-18", Error: "database is down"}}, runs)
+	assert.Equal(t, "2026-10-17", s.LastDays()["flaky"])
+
+	fail = false
+	assert.Len(t, s.RunDue(context.Background()), 2)
+	assert.Equal(t, "2026-10-19", s.LastDays()["flaky"])
+}
@@ -0,0 +1,239 @@
+package screen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+type Screen string
+
+const (
+	Welcome             Screen = "WELCOME"
+	Transaction         Screen = "TRANSACTION"
+	Withdraw            Screen = "WITHDRAW"
+	OtherWithdraw       Screen = "OTHER_WITHDRAW"
+	WithdrawSummary     Screen = "WITHDRAW_SUMMARY"
+	FundTransfer        Screen = "FUND_TRANSFER"
+	FundTransferSummary Screen = "FUND_TRANSFER_SUMMARY"
+	BillPayment         Screen = "BILL_PAYMENT"
+	BillPaymentSummary  Screen = "BILL_PAYMENT_SUMMARY"
+	Cardless            Screen = "CARDLESS"
+	StandingOrders      Screen = "STANDING_ORDERS"
+)
+
+type Event string
+
+const (
+	// Operation events, fired by the endpoint performing the operation
+	EventLogin    Event = "LOGIN"
+	EventFastCash Event = "FAST_CASH"
+	EventWithdraw Event = "WITHDRAW"
+	EventTransfer Event = "TRANSFER"
+	EventExit     Event = "EXIT"
+
+	EventPayBill             Event = "PAY_BILL"
+	EventCreateCardless      Event = "CREATE_CARDLESS"
+	EventCancelCardless      Event = "CANCEL_CARDLESS"
+	EventCreateStandingOrder Event = "CREATE_STANDING_ORDER"
+	EventCancelStandingOrder Event = "CANCEL_STANDING_ORDER"
+
+	// Navigation events, fired by the client through the screen endpoint
+	EventWithdrawMenu      Event = "WITHDRAW_MENU"
+	EventFundTransferMenu  Event = "FUND_TRANSFER_MENU"
+	EventBillPaymentMenu   Event = "BILL_PAYMENT_MENU"
+	EventCardlessMenu      Event = "CARDLESS_MENU"
+	EventStandingOrderMenu Event = "STANDING_ORDER_MENU"
+	EventOtherAmount       Event = "OTHER_AMOUNT"
+	EventTransactionMenu   Event = "TRANSACTION_MENU"
+	EventBack              Event = "BACK"
+)
+
+var ErrInvalidTransition = errors.New("operation is not allowed on the current screen")
+
+var operations = map[Event]bool{
+	EventLogin:    true,
+	EventFastCash: true,
+	EventWithdraw: true,
+	EventTransfer: true,
+	EventExit:     true,
+
+	EventPayBill:             true,
+	EventCreateCardless:      true,
+	EventCancelCardless:      true,
+	EventCreateStandingOrder: true,
+	EventCancelStandingOrder: true,
+}
+
+type Option struct {
+	Event    Event   `json:"event"`
+	Label    string  `json:"label"`
+	Next     Screen  `json:"next"`
+	Amount   float64 `json:"amount,omitempty"`
+	Disabled bool    `json:"disabled,omitempty"`
+}
+
+type State struct {
+	Screen  Screen   `json:"screen"`
+	Options []Option `json:"options"`
+}
+
+// transitions lists the options of each screen in the order they are displayed
+var transitions = map[Screen][]Option{
+	Welcome: {
+		{Event: EventLogin, Label: "Login", Next: Transaction},
+	},
+	Transaction: {
+		{Event: EventWithdrawMenu, Label: "Withdraw", Next: Withdraw},
+		{Event: EventFundTransferMenu, Label: "Fund Transfer", Next: FundTransfer},
+		{Event: EventBillPaymentMenu, Label: "Bill Payment", Next: BillPayment},
+		{Event: EventCardlessMenu, Label: "Cardless Withdrawal", Next: Cardless},
+		{Event: EventStandingOrderMenu, Label: "Standing Orders", Next: StandingOrders},
+		{Event: EventExit, Label: "Exit", Next: Welcome},
+	},
+	Withdraw: {
+		{Event: EventFastCash, Label: "Fast cash", Next: WithdrawSummary},
+		{Event: EventOtherAmount, Label: "Other", Next: OtherWithdraw},
+		{Event: EventBack, Label: "Back", Next: Transaction},
+	},
+	OtherWithdraw: {
+		{Event: EventWithdraw, Label: "Withdraw", Next: WithdrawSummary},
+		{Event: EventBack, Label: "Back", Next: Withdraw},
+	},
+	WithdrawSummary: {
+		{Event: EventTransactionMenu, Label: "Transaction", Next: Transaction},
+		{Event: EventExit, Label: "Exit", Next: Welcome},
+	},
+	FundTransfer: {
+		{Event: EventTransfer, Label: "Confirm Trx", Next: FundTransferSummary},
+		{Event: EventBack, Label: "Cancel Trx", Next: Transaction},
+	},
+	FundTransferSummary: {
+		{Event: EventTransactionMenu, Label: "Transaction", Next: Transaction},
+		{Event: EventExit, Label: "Exit", Next: Welcome},
+	},
+	BillPayment: {
+		{Event: EventPayBill, Label: "Pay", Next: BillPaymentSummary},
+		{Event: EventBack, Label: "Back", Next: Transaction},
+	},
+	BillPaymentSummary: {
+		{Event: EventTransactionMenu, Label: "Transaction", Next: Transaction},
+		{Event: EventExit, Label: "Exit", Next: Welcome},
+	},
+	// codes and orders are created and cancelled on their own screen, the customer goes back when done
+	Cardless: {
+		{Event: EventCreateCardless, Label: "Create code", Next: Cardless},
+		{Event: EventCancelCardless, Label: "Cancel code", Next: Cardless},
+		{Event: EventBack, Label: "Back", Next: Transaction},
+	},
+	StandingOrders: {
+		{Event: EventCreateStandingOrder, Label: "Create order", Next: StandingOrders},
+		{Event: EventCancelStandingOrder, Label: "Cancel order", Next: StandingOrders},
+		{Event: EventBack, Label: "Back", Next: Transaction},
+	},
+}
+
+// WithFastCash replaces the fast cash option with one option per preset of the ATM
+func (s State) WithFastCash(presets []entity.FastCashPreset) State {
+	options := make([]Option, 0, len(s.Options)+len(presets))
+	for _, o := range s.Options {
+		if o.Event != EventFastCash {
+			options = append(options, o)
+			continue
+		}
+		for _, p := range presets {
+			options = append(options, Option{
+				Event:    EventFastCash,
+				Label:    fmt.Sprintf("$%0.f", p.Amount),
+				Next:     o.Next,
+				Amount:   p.Amount,
+				Disabled: !p.Available,
+			})
+		}
+	}
+	s.Options = options
+	return s
+}
+
+// IsOperation reports whether the event can only be fired by performing the operation itself
+func IsOperation(e Event) bool {
+	return operations[e]
+}
+
+// Machine tracks the current screen of every ATM session.
+// Sessions that are not tracked are on the Welcome screen.
+type Machine struct {
+	mu       sync.Mutex
+	sessions map[string]Screen
+}
+
+func New() *Machine {
+	return &Machine{sessions: make(map[string]Screen)}
+}
+
+// Start opens a new session right after a successful login
+func (m *Machine) Start() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	sessionID := hex.EncodeToString(b)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[sessionID] = next(Welcome, EventLogin)
+	return sessionID
+}
+
+// End brings the session back to the Welcome screen from any screen, it is how a session is logged out
+func (m *Machine) End(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+}
+
+func (m *Machine) Current(sessionID string) State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.current(sessionID)
+	return State{Screen: s, Options: transitions[s]}
+}
+
+func (m *Machine) Can(sessionID string, e Event) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return next(m.current(sessionID), e) != ""
+}
+
+// Fire moves the session to the screen that follows e
+func (m *Machine) Fire(sessionID string, e Event) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := next(m.current(sessionID), e)
+	if s == "" {
+		return State{}, ErrInvalidTransition
+	}
+	if s == Welcome {
+		delete(m.sessions, sessionID)
+	} else {
+		m.sessions[sessionID] = s
+	}
+	return State{Screen: s, Options: transitions[s]}, nil
+}
+
+func (m *Machine) current(sessionID string) Screen {
+	if s, ok := m.sessions[sessionID]; ok {
+		return s
+	}
+	return Welcome
+}
+
+func next(s Screen, e Event) Screen {
+	for _, o := range transitions[s] {
+		if o.Event == e {
+			return o.Next
+		}
+	}
+	return ""
+}
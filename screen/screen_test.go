@@ -0,0 +1,104 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachine_UnknownSessionIsOnWelcome(t *testing.T) {
+	m := New()
+	assert.Equal(t, Welcome, m.Current("unknown").Screen)
+	assert.False(t, m.Can("unknown", EventWithdraw))
+}
+
+func TestMachine_LoginGoesToTransaction(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	assert.Equal(t, Transaction, m.Current(sessionID).Screen)
+}
+
+func TestMachine_WithdrawFlow(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	assert.False(t, m.Can(sessionID, EventWithdraw))
+
+	state, err := m.Fire(sessionID, EventWithdrawMenu)
+	assert.Nil(t, err)
+	assert.Equal(t, Withdraw, state.Screen)
+
+	state, err = m.Fire(sessionID, EventOtherAmount)
+	assert.Nil(t, err)
+	assert.Equal(t, OtherWithdraw, state.Screen)
+
+	state, err = m.Fire(sessionID, EventBack)
+	assert.Nil(t, err)
+	assert.Equal(t, Withdraw, state.Screen)
+
+	assert.False(t, m.Can(sessionID, EventWithdraw))
+	state, err = m.Fire(sessionID, EventFastCash)
+	assert.Nil(t, err)
+	assert.Equal(t, WithdrawSummary, state.Screen)
+}
+
+func TestState_WithFastCash(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	state, _ := m.Fire(sessionID, EventWithdrawMenu)
+	state = state.WithFastCash([]entity.FastCashPreset{
+		{Amount: 20, Available: true},
+		{Amount: 200, Available: false},
+	})
+	assert.Equal(t, []Option{
+		{Event: EventFastCash, Label: "$20", Next: WithdrawSummary, Amount: 20},
+		{Event: EventFastCash, Label: "$200", Next: WithdrawSummary, Amount: 200, Disabled: true},
+		{Event: EventOtherAmount, Label: "Other", Next: OtherWithdraw},
+		{Event: EventBack, Label: "Back", Next: Transaction},
+	}, state.Options)
+}
+
+func TestMachine_ExitGoesBackToWelcome(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	m.Fire(sessionID, EventFundTransferMenu)
+	m.Fire(sessionID, EventTransfer)
+
+	state, err := m.Fire(sessionID, EventExit)
+	assert.Nil(t, err)
+	assert.Equal(t, Welcome, state.Screen)
+	assert.Equal(t, Welcome, m.Current(sessionID).Screen)
+}
+
+func TestMachine_EndFromAnyScreen(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	m.Fire(sessionID, EventWithdrawMenu)
+	m.Fire(sessionID, EventOtherAmount)
+	assert.False(t, m.Can(sessionID, EventExit))
+
+	m.End(sessionID)
+	assert.Equal(t, Welcome, m.Current(sessionID).Screen)
+}
+
+func TestMachine_CardlessAndStandingOrderScreens(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	assert.False(t, m.Can(sessionID, EventCreateCardless))
+	assert.False(t, m.Can(sessionID, EventPayBill))
+
+	m.Fire(sessionID, EventCardlessMenu)
+	state, err := m.Fire(sessionID, EventCreateCardless)
+	assert.Nil(t, err)
+	assert.Equal(t, Cardless, state.Screen)
+	assert.False(t, m.Can(sessionID, EventCreateStandingOrder))
+	assert.True(t, IsOperation(EventCancelCardless))
+}
+
+func TestMachine_InvalidTransition(t *testing.T) {
+	m := New()
+	sessionID := m.Start()
+	_, err := m.Fire(sessionID, EventTransfer)
+	assert.Equal(t, ErrInvalidTransition, err)
+	assert.Equal(t, Transaction, m.Current(sessionID).Screen)
+}
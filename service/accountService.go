@@ -2,67 +2,147 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/ledger"
 	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
 )
 
+// Limits of a transfer in the base currency
+const (
+	maxTransferAmount = 1000
+	minTransferAmount = 1
+)
+
 var accMap = make(map[string]*entity.Account)
 
 func initData() {
+	accMap = make(map[string]*entity.Account)
 	accMap["112233"] = &entity.Account{
 		Name:          "John Doe",
 		PIN:           "012108",
 		Balance:       100,
-		AccountNumber: "112233"}
+		AccountNumber: "112233",
+		Status:        entity.AccountActive}
 
 	accMap["112244"] = &entity.Account{
 		Name:          "Jane Doe",
 		PIN:           "932012",
 		Balance:       100,
-		AccountNumber: "112244"}
+		AccountNumber: "112244",
+		Status:        entity.AccountActive}
 }
 
-func (s *Service) PINValidation(c context.Context, account entity.Account) *responseFormatter.ResponseFormatter {
-	if strings.Trim(account.AccountNumber, " ") == "" {
+// validateCredentials checks the format of an account number and PIN
+func validateCredentials(acctNbr, pin string) *responseFormatter.ResponseFormatter {
+	if strings.Trim(acctNbr, " ") == "" {
 		return responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
-	} else if strings.Trim(account.PIN, " ") == "" {
+	} else if strings.Trim(pin, " ") == "" {
 		return responseFormatter.New(http.StatusBadRequest, "PIN is required", true)
-	} else if len(account.AccountNumber) < 6 {
+	} else if len(acctNbr) < 6 {
 		return responseFormatter.New(http.StatusBadRequest, "Account Number should have 6 digits length", true)
-	} else if len(account.PIN) < 6 {
+	} else if len(pin) < 6 {
 		return responseFormatter.New(http.StatusBadRequest, "PIN should have 6 digits length", true)
-	} else if _, err := strconv.Atoi(account.AccountNumber); err != nil {
+	} else if _, err := strconv.Atoi(acctNbr); err != nil {
 		return responseFormatter.New(http.StatusBadRequest, "Account Number should only contains numbers", true)
-	} else if _, err := strconv.Atoi(account.PIN); err != nil {
+	} else if _, err := strconv.Atoi(pin); err != nil {
 		return responseFormatter.New(http.StatusBadRequest, "PIN should only contains numbers", true)
-	} else if accMap[account.AccountNumber] == nil || accMap[account.AccountNumber].PIN != account.PIN {
-		return responseFormatter.New(http.StatusBadRequest, "Invalid Account Number/PIN", true)
+	}
+	return nil
+}
+
+// PINValidation authenticates a card. The session starts on the first account of the card
+// that is neither frozen nor closed.
+func (s *Service) PINValidation(c context.Context, login entity.CardLogin) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	c, unlock := s.lock(c)
+	defer unlock()
+	if resp := validateCardLogin(login); resp != nil {
+		return nil, resp
+	}
+	card := cardMap[login.CardNumber]
+	if card == nil || card.PIN != login.PIN {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Card Number/PIN", true)
+	} else if resp := s.checkCardStatus(card); resp != nil {
+		return nil, resp
+	}
+	var resp *responseFormatter.ResponseFormatter
+	for _, acctNbr := range card.AccountNumbers {
+		acc := accMap[acctNbr]
+		if acc == nil {
+			continue
+		} else if accResp := checkAccountStatus(acc); accResp != nil {
+			if resp == nil {
+				resp = accResp
+			}
+			continue
+		}
+		return s.toAccountResponse(acc), nil
+	}
+	if resp == nil {
+		resp = responseFormatter.New(http.StatusForbidden, "Card has no account", true)
+	}
+	return nil, resp
+}
+
+// checkAccountStatus rejects frozen and closed accounts
+func checkAccountStatus(acc *entity.Account) *responseFormatter.ResponseFormatter {
+	switch acc.Status {
+	case entity.AccountFrozen:
+		return responseFormatter.New(http.StatusForbidden, "Account is frozen", true).WithCode(ErrCodeAccountFrozen)
+	case entity.AccountClosed:
+		return responseFormatter.New(http.StatusForbidden, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	}
+	return nil
+}
+
+func checkWithdrawAmount(amount float64) *responseFormatter.ResponseFormatter {
+	if amount <= 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid withdraw amount", true)
+	} else if amount > 1000 {
+		return responseFormatter.New(http.StatusBadRequest, "Maximum amount to withdraw is $1000", true)
+	} else if int(amount)%10 != 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid ammount", true)
 	}
 	return nil
 }
 
 func (s *Service) Withdraw(ctx context.Context, accountNumber string, withdrawAmount float64) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
 	if accountNumber == "" {
 		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
-	} else if withdrawAmount <= 0 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid withdraw amount", true)
-	} else if withdrawAmount > 1000 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Maximum amount to withdraw is $1000", true)
-	} else if int(withdrawAmount)%10 != 0 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid ammount", true)
-	} else if accMap[accountNumber].Balance < withdrawAmount {
-		return nil, responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Insufficient balance $%0.f", withdrawAmount), true)
-	}
-	accMap[accountNumber].Balance -= withdrawAmount
-	return accMap[accountNumber].ToAccountResponse(), nil
+	} else if resp := checkWithdrawAmount(withdrawAmount); resp != nil {
+		return nil, resp
+	} else if accMap[accountNumber] == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	} else if resp := checkAccountStatus(accMap[accountNumber]); resp != nil {
+		return nil, resp
+	} else if resp := checkBaseCurrency(accMap[accountNumber], "Cash withdrawal"); resp != nil {
+		return nil, resp
+	} else if charge := s.fee(accMap[accountNumber], fee.OnUs, fee.Withdraw, withdrawAmount); s.spendableBalance(accMap[accountNumber]) < withdrawAmount+charge {
+		return nil, insufficientBalance(accMap[accountNumber], withdrawAmount, charge)
+	} else if resp := s.checkWithdrawRules(accMap[accountNumber], withdrawAmount, charge); resp != nil {
+		return nil, resp
+	} else if resp := s.checkStepUp(ctx, accMap[accountNumber], s.aboveStepUpThreshold(accMap[accountNumber], withdrawAmount)); resp != nil {
+		return nil, resp
+	}
+	atm, resp := s.getATM(ctx)
+	if resp != nil {
+		return nil, resp
+	} else if atm.Cash < withdrawAmount {
+		return nil, responseFormatter.New(http.StatusServiceUnavailable, "ATM does not have enough cash, please try a smaller amount", true)
+	}
+	return s.dispense(ctx, atm, accountNumber, withdrawAmount)
 }
 
 func (s *Service) BalanceCheck(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
 	if strings.Trim(acctNbr, " ") == "" {
 		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
 	} else if len(acctNbr) < 6 {
@@ -72,10 +152,40 @@ func (s *Service) BalanceCheck(ctx context.Context, acctNbr string) (*entity.Acc
 	} else if accMap[acctNbr] == nil {
 		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Account Number/PIN", true)
 	}
-	return accMap[acctNbr].ToAccountResponse(), nil
+	return s.toAccountResponse(accMap[acctNbr]), nil
+}
+
+// checkTransferAmount checks a transfer from acc against the limits, they are in the base currency
+// and converted to the currency of acc
+func (s *Service) checkTransferAmount(acc *entity.Account, amount float64) *responseFormatter.ResponseFormatter {
+	if max, min := s.fromBase(acc, maxTransferAmount), s.fromBase(acc, minTransferAmount); amount <= 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid transfer amount", true)
+	} else if amount > max {
+		return responseFormatter.New(http.StatusBadRequest, "Maximum amount to transfer is "+fx.FormatWhole(currencyOf(acc), max), true)
+	} else if amount < min {
+		return responseFormatter.New(http.StatusBadRequest, "Minimum amount to transfer is "+fx.FormatWhole(currencyOf(acc), min), true)
+	}
+	return nil
 }
 
+// Transfer moves money to ToAccountNumber, or to the saved beneficiary BeneficiaryID of the source account
 func (s *Service) Transfer(ctx context.Context, transfer entity.Transfer) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if transfer.BeneficiaryID != "" {
+		b, resp := findBeneficiary(transfer.FromAccountNumber, transfer.BeneficiaryID)
+		if resp != nil {
+			return nil, resp
+		} else if (transfer.ToAccountNumber != "" && transfer.ToAccountNumber != b.ToAccountNumber) ||
+			(transfer.BankCode != "" && transfer.BankCode != b.BankCode) {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Destination account does not match the beneficiary", true)
+		}
+		transfer.ToAccountNumber = b.ToAccountNumber
+		transfer.BankCode = b.BankCode
+	}
+	if transfer.BankCode != "" && transfer.BankCode != BankCode() {
+		return s.interbankTransfer(ctx, transfer)
+	}
 	if transfer.FromAccountNumber == "" || transfer.ToAccountNumber == "" {
 		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
 	} else if transfer.FromAccountNumber == transfer.ToAccountNumber {
@@ -86,20 +196,75 @@ func (s *Service) Transfer(ctx context.Context, transfer entity.Transfer) (*enti
 		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
 	} else if accMap[transfer.ToAccountNumber] == nil {
 		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
-	} else if transfer.Amount <= 0 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid transfer amount", true)
-	} else if transfer.Amount > 1000 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Maximum amount to transfer is $1000", true)
-	} else if transfer.Amount < 1 {
-		return nil, responseFormatter.New(http.StatusBadRequest, "Minimum amount to transfer is $1", true)
-	} else if accMap[transfer.FromAccountNumber].Balance < transfer.Amount {
-		return nil, responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Insufficient balance $%0.f", transfer.Amount), true)
+	} else if resp := checkAccountStatus(accMap[transfer.FromAccountNumber]); resp != nil {
+		return nil, resp
+	} else if accMap[transfer.ToAccountNumber].Status == entity.AccountFrozen ||
+		accMap[transfer.ToAccountNumber].Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Destination account cannot receive transfers", true).
+			WithCode(ErrCodeDestinationUnavailable)
+	} else if resp := s.checkTransferAmount(accMap[transfer.FromAccountNumber], transfer.Amount); resp != nil {
+		return nil, resp
+	}
+	from, to := accMap[transfer.FromAccountNumber], accMap[transfer.ToAccountNumber]
+	charge := s.fee(from, fee.OnUs, fee.Transfer, transfer.Amount)
+	if s.spendableBalance(from) < transfer.Amount+charge {
+		return nil, insufficientBalance(from, transfer.Amount, charge)
+	} else if resp := s.checkTransferRules(from, to, transfer.Amount, charge); resp != nil {
+		return nil, resp
 	} else if strings.Trim(transfer.ReferenceNumber, " ") != "" {
-		if _, err = strconv.Atoi(transfer.ReferenceNumber); err != nil {
+		if _, err := strconv.Atoi(transfer.ReferenceNumber); err != nil {
 			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Reference Number", true)
 		}
 	}
-	accMap[transfer.FromAccountNumber].Balance -= transfer.Amount
-	accMap[transfer.ToAccountNumber].Balance += transfer.Amount
-	return accMap[transfer.FromAccountNumber].ToAccountResponse(), nil
+	conv, quote, resp := s.transferConversion(transfer, from, to)
+	if resp != nil {
+		return nil, resp
+	} else if resp := s.checkStepUp(ctx, from, s.transferStepUp(from, BankCode(), to.AccountNumber, transfer.Amount)); resp != nil {
+		return nil, resp
+	}
+	out := entity.Transaction{
+		AccountNumber:            transfer.FromAccountNumber,
+		Type:                     entity.TransactionTransferOut,
+		Amount:                   transfer.Amount,
+		CounterpartAccountNumber: transfer.ToAccountNumber,
+		ReferenceNumber:          transfer.ReferenceNumber,
+	}
+	in := entity.Transaction{
+		AccountNumber:            transfer.ToAccountNumber,
+		Type:                     entity.TransactionTransferIn,
+		Amount:                   transfer.Amount,
+		CounterpartAccountNumber: transfer.FromAccountNumber,
+		ReferenceNumber:          transfer.ReferenceNumber,
+	}
+	entry := ledger.TransferEntry(transfer.FromAccountNumber, transfer.ToAccountNumber, transfer.Amount)
+	if conv.From != conv.To {
+		in.Amount = conv.Convert(transfer.Amount)
+		out.CounterpartAmount, in.CounterpartAmount = in.Amount, out.Amount
+		out.FXRate, in.FXRate = conv.Rate, conv.Rate
+		entry = ledger.FXTransferEntry(transfer.FromAccountNumber, conv.From, out.Amount, transfer.ToAccountNumber, conv.To, in.Amount)
+	}
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return nil, resp
+	}
+	if quote != nil {
+		usedAt := s.clock.Now()
+		quote.UsedAt = &usedAt
+	}
+	out.ID = entry.TransactionID
+	trx := s.recordTransaction(ctx, out)
+	s.recordTransaction(ctx, in)
+	if resp := s.chargeFee(ctx, trx, charge); resp != nil {
+		return nil, resp
+	}
+	s.save()
+	accResp := s.toAccountResponse(accMap[transfer.FromAccountNumber])
+	accResp.TransactionID = trx.ID
+	accResp.Fee = charge
+	if conv.From != conv.To {
+		accResp.ConvertedAmount = in.Amount
+		accResp.ConvertedCurrency = conv.To
+		accResp.FXRate = conv.Rate
+	}
+	return accResp, nil
 }
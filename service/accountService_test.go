@@ -10,99 +10,111 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestPinValidation_AccountNbrIsRequired(t *testing.T) {
+// Cards issued to the initial accounts
+const (
+	johnCard = "4000000000000010"
+	janeCard = "4000000000000028"
+)
+
+func TestPinValidation_CardNumberIsRequired(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
 		PIN: "456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	assert.Equal(t, "Account Number is required", resp.Message)
+	assert.Equal(t, "Card Number is required", resp.Message)
 }
 
 func TestPinValidation_PINIsRequired(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "123",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: "123",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.Equal(t, "PIN is required", resp.Message)
 }
 
-// - Account Number should have 6 digits length. Display message `Account Number should have 6 digits length` for invalid Account Number.
-func TestPinValidation_AccountNumberMustSixDigitsLength(t *testing.T) {
+func TestPinValidation_CardNumberMustSixteenDigitsLength(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "123",
-		PIN:           "456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: "112233",
+		PIN:        "456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	assert.Equal(t, "Account Number should have 6 digits length", resp.Message)
+	assert.Equal(t, "Card Number should have 16 digits length", resp.Message)
 }
 
 //- PIN should have 6 digits length. Display message `PIN should have 6 digits length` for invalid PIN.
 
 func TestPinValidation_PINMustSixDigitsLength(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "123456",
-		PIN:           "456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: johnCard,
+		PIN:        "456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.Equal(t, "PIN should have 6 digits length", resp.Message)
 }
 
-// - Account Number should only contains numbers [0-9]. Display message `Account Number should only contains numbers` for invalid Account Number.
-func TestPinValidation_AccountNumberOnlyContainsNumber(t *testing.T) {
+func TestPinValidation_CardNumberOnlyContainsNumber(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "a123456",
-		PIN:           "123456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: "a000000000000010",
+		PIN:        "123456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	assert.Equal(t, "Account Number should only contains numbers", resp.Message)
+	assert.Equal(t, "Card Number should only contains numbers", resp.Message)
 }
 
 // - PIN should only contains numbers [0-9]. Display message `PIN should only contains numbers` for invalid PIN.
 func TestPinValidation_PINOnlyContainsNumber(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "123456",
-		PIN:           "a123456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: johnCard,
+		PIN:        "a123456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.Equal(t, "PIN should only contains numbers", resp.Message)
 }
 
-//- Check valid Acccount Number & PIN with ATM records. Display message `Invalid Account Number/PIN` if records is not exist.
+func TestPinValidation_CardNumberFailsLuhnCheck(t *testing.T) {
+	svc := New()
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: "4000000000000011",
+		PIN:        "012108",
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid Card Number", resp.Message)
+}
 
-func TestPinValidation_InvalidAccountNumber(t *testing.T) {
+func TestPinValidation_InvalidCardNumber(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "123456",
-		PIN:           "1123456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: "4111111111111111",
+		PIN:        "012108",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	assert.Equal(t, "Invalid Account Number/PIN", resp.Message)
+	assert.Equal(t, "Invalid Card Number/PIN", resp.Message)
 }
 
-// - Check valid Acccount Number & PIN with ATM records. Display message `Invalid Account Number/PIN` if records is not exist.
 func TestPinValidation_InvalidPIN(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "112233",
-		PIN:           "1123456",
+	_, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: johnCard,
+		PIN:        "1123456",
 	})
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	assert.Equal(t, "Invalid Account Number/PIN", resp.Message)
+	assert.Equal(t, "Invalid Card Number/PIN", resp.Message)
 }
 
 func TestPinValidation_Success(t *testing.T) {
 	svc := New()
-	resp := svc.PINValidation(context.Background(), entity.Account{
-		AccountNumber: "112233",
-		PIN:           "012108",
+	acc, resp := svc.PINValidation(context.Background(), entity.CardLogin{
+		CardNumber: johnCard,
+		PIN:        "012108",
 	})
 	assert.Nil(t, resp)
+	assert.Equal(t, "112233", acc.AccountNumber)
 }
 
 // - Maximum amount to withdraw is $1000. Display message `Maximum amount to withdraw is $1000` if withdraw amount is higher than $1000.
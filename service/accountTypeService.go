@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+var accountTypes = map[entity.AccountType]entity.AccountTypeRules{
+	entity.AccountSavings: {
+		Type:               entity.AccountSavings,
+		WithdrawLimit:      500,
+		MinimumBalance:     50,
+		CanTransferOut:     false,
+		CanReceiveTransfer: true,
+	},
+	entity.AccountChecking: {
+		Type:               entity.AccountChecking,
+		WithdrawLimit:      1000,
+		CanTransferOut:     true,
+		CanReceiveTransfer: true,
+	},
+	entity.AccountCheckingOverdraft: {
+		Type:               entity.AccountCheckingOverdraft,
+		WithdrawLimit:      1000,
+		CanTransferOut:     true,
+		CanReceiveTransfer: true,
+		Overdraft:          true,
+	},
+}
+
+// accountTypeOf is the type of acc, accounts saved before there were types are checking accounts
+func accountTypeOf(acc *entity.Account) entity.AccountTypeRules {
+	if rules, ok := accountTypes[acc.Type]; ok {
+		return rules
+	}
+	return accountTypes[entity.DefaultAccountType]
+}
+
+func typeName(t entity.AccountType) string {
+	return strings.ToLower(strings.ReplaceAll(string(t), "_", " "))
+}
+
+// checkWithdrawRules checks a withdrawal, and its fee, against the rules of the account's type
+func (s *Service) checkWithdrawRules(acc *entity.Account, amount, fee float64) *responseFormatter.ResponseFormatter {
+	rules := accountTypeOf(acc)
+	if limit := s.fromBase(acc, rules.WithdrawLimit); amount > limit {
+		return responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Maximum amount to withdraw from a %s account is %s", typeName(rules.Type), fx.FormatWhole(currencyOf(acc), limit)), true)
+	}
+	return s.checkMinimumBalance(acc, amount+fee)
+}
+
+// checkTransferRules checks a transfer, and its fee, against the rules of both account types
+func (s *Service) checkTransferRules(from, to *entity.Account, amount, fee float64) *responseFormatter.ResponseFormatter {
+	if resp := checkTransferOut(from); resp != nil {
+		return resp
+	} else if !accountTypeOf(to).CanReceiveTransfer {
+		return responseFormatter.New(http.StatusBadRequest, "Destination account cannot receive transfers", true).
+			WithCode(ErrCodeDestinationUnavailable)
+	}
+	return s.checkMinimumBalance(from, amount+fee)
+}
+
+// checkTransferOut checks the account type of from allows transfers out of it
+func checkTransferOut(from *entity.Account) *responseFormatter.ResponseFormatter {
+	if rules := accountTypeOf(from); !rules.CanTransferOut {
+		return responseFormatter.New(http.StatusForbidden,
+			fmt.Sprintf("Transfers are not allowed from a %s account", typeName(rules.Type)), true).
+			WithCode(ErrCodeTransferNotAllowed)
+	}
+	return nil
+}
+
+func (s *Service) checkMinimumBalance(acc *entity.Account, debit float64) *responseFormatter.ResponseFormatter {
+	rules := accountTypeOf(acc)
+	if min := s.fromBase(acc, rules.MinimumBalance); min > 0 && s.availableBalance(acc)-debit < min {
+		return responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Balance cannot go below the %s minimum balance of a %s account", fx.FormatWhole(currencyOf(acc), min), typeName(rules.Type)), true).
+			WithCode(ErrCodeInsufficientBalance)
+	}
+	return nil
+}
+
+func (s *Service) AccountTypes(ctx context.Context) []entity.AccountTypeRules {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return []entity.AccountTypeRules{
+		accountTypes[entity.AccountSavings],
+		accountTypes[entity.AccountChecking],
+		accountTypes[entity.AccountCheckingOverdraft],
+	}
+}
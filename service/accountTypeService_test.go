@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountType_DefaultsToChecking(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, resp := svc.BalanceCheck(ctx, "112233")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.AccountChecking, acc.Type)
+
+	_, resp = svc.CreateAccount(ctx, entity.Account{Name: "Ann", AccountNumber: "445566", PIN: "445566", Type: "BROKERAGE"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAccountType_SavingsRules(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.CreateAccount(ctx, entity.Account{
+		Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 1000, Type: entity.AccountSavings,
+	})
+	assert.Nil(t, resp)
+
+	_, resp = svc.Withdraw(ctx, "445566", 600)
+	assert.Equal(t, "Maximum amount to withdraw from a savings account is $500", resp.Message)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 10})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, ErrCodeTransferNotAllowed, resp.Code)
+
+	_, resp = svc.Withdraw(ctx, "445566", 500)
+	assert.Nil(t, resp)
+	_, resp = svc.Withdraw(ctx, "445566", 460)
+	assert.Equal(t, "Balance cannot go below the $50 minimum balance of a savings account", resp.Message)
+	acc, resp := svc.Withdraw(ctx, "445566", 450)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(50), acc.Balance)
+
+	acc, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "445566", Amount: 20})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(80), acc.Balance)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+func (s *Service) CreateAccount(ctx context.Context, account entity.Account) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	account.Currency = strings.ToUpper(account.Currency)
+	if resp := s.validateNewAccount(account); resp != nil {
+		return nil, resp
+	}
+	account.Status = entity.AccountActive
+	account.OverdraftInterestOn = ""
+	account.TOTP = nil
+	if resp := s.openAccount(&account); resp != nil {
+		return nil, resp
+	}
+	s.save()
+	return s.toAccountResponse(&account), nil
+}
+
+func (s *Service) validateNewAccount(account entity.Account) *responseFormatter.ResponseFormatter {
+	if resp := validateCredentials(account.AccountNumber, account.PIN); resp != nil {
+		return resp
+	} else if strings.Trim(account.Name, " ") == "" {
+		return responseFormatter.New(http.StatusBadRequest, "Name is required", true)
+	} else if _, ok := accountTypes[account.Type]; account.Type != "" && !ok {
+		return responseFormatter.New(http.StatusBadRequest, "Account type should be SAVINGS, CHECKING or CHECKING_OVERDRAFT", true)
+	} else if account.Balance < 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Initial balance cannot be negative", true)
+	} else if math.IsNaN(account.Balance) || math.IsInf(account.Balance, 0) {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid initial balance", true)
+	} else if account.OverdraftLimit < 0 || account.OverdraftRate < 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Overdraft limit and rate cannot be negative", true)
+	} else if account.OverdraftLimit > 0 && account.Type != entity.AccountCheckingOverdraft {
+		return responseFormatter.New(http.StatusBadRequest, "Overdraft limit is only allowed on CHECKING_OVERDRAFT accounts", true)
+	} else if account.Currency != "" && !s.fx.Supported(account.Currency) {
+		return responseFormatter.New(http.StatusBadRequest, "Currency should be "+fx.Base+" or a currency with an exchange rate", true)
+	} else if accMap[account.AccountNumber] != nil {
+		return responseFormatter.New(http.StatusConflict, "Account Number already exists", true)
+	}
+	return nil
+}
+
+func (s *Service) GetAccount(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	}
+	return s.toAccountResponse(acc), nil
+}
+
+func (s *Service) ListAccounts(ctx context.Context) []*entity.AccountResponse {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	accounts := make([]*entity.AccountResponse, 0, len(accMap))
+	for _, acc := range accMap {
+		accounts = append(accounts, s.toAccountResponse(acc))
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].AccountNumber < accounts[j].AccountNumber
+	})
+	return accounts
+}
+
+// UpdateAccount changes the account holder name, balances only change through transactions
+func (s *Service) UpdateAccount(ctx context.Context, acctNbr string, name string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if strings.Trim(name, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Name is required", true)
+	} else if acc.Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	}
+	acc.Name = name
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+// ResetPIN changes the PIN of the account and of every card that can access it
+func (s *Service) ResetPIN(ctx context.Context, acctNbr string, pin string) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if resp := validateCredentials(acctNbr, pin); resp != nil {
+		return resp
+	}
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return resp
+	} else if acc.Status == entity.AccountClosed {
+		return responseFormatter.New(http.StatusBadRequest, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	}
+	acc.PIN = pin
+	for _, c := range cardsOf(acctNbr) {
+		c.PIN = pin
+	}
+	s.save()
+	return nil
+}
+
+func (s *Service) FreezeAccount(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.setAccountStatus(acctNbr, entity.AccountActive, entity.AccountFrozen)
+}
+
+func (s *Service) UnfreezeAccount(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.setAccountStatus(acctNbr, entity.AccountFrozen, entity.AccountActive)
+}
+
+// CloseAccount closes an account with no money left on it and nothing pending on it, closed accounts are kept for their history
+func (s *Service) CloseAccount(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account is already closed", true).WithCode(ErrCodeAccountClosed)
+	} else if acc.Balance != 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account balance should be zero before closing", true)
+	} else if resp := s.checkNothingPending(acc); resp != nil {
+		return nil, resp
+	}
+	acc.Status = entity.AccountClosed
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+// checkNothingPending rejects closing an account that still has money reserved or payments to make :
+// pending cardless withdrawals, other active holds or active standing orders have to be cancelled first
+func (s *Service) checkNothingPending(acc *entity.Account) *responseFormatter.ResponseFormatter {
+	for _, w := range cardlessList {
+		if w.AccountNumber == acc.AccountNumber && s.refreshCardless(w).Status == entity.CardlessPending {
+			return responseFormatter.New(http.StatusBadRequest, "Cancel the pending cardless withdrawals before closing", true)
+		}
+	}
+	now := s.clock.Now()
+	for _, h := range holdList {
+		if h.AccountNumber == acc.AccountNumber && h.Active(now) {
+			return responseFormatter.New(http.StatusBadRequest, "Release the active holds before closing", true)
+		}
+	}
+	for _, o := range standingOrderList {
+		if o.FromAccountNumber == acc.AccountNumber && o.Status == entity.StandingOrderActive {
+			return responseFormatter.New(http.StatusBadRequest, "Cancel the standing orders before closing", true)
+		}
+	}
+	return nil
+}
+
+func (s *Service) setAccountStatus(acctNbr string, from, to entity.AccountStatus) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	} else if acc.Status != from {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account is already "+strings.ToLower(string(to)), true)
+	}
+	acc.Status = to
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+func findAccount(acctNbr string) (*entity.Account, *responseFormatter.ResponseFormatter) {
+	if strings.Trim(acctNbr, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	} else if accMap[acctNbr] == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Account not found", true)
+	}
+	return accMap[acctNbr], nil
+}
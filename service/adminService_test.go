@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAccount_SameRulesAsPINValidation(t *testing.T) {
+	svc := New()
+	_, resp := svc.CreateAccount(context.Background(), entity.Account{
+		Name:          "Richard Roe",
+		AccountNumber: "a12345",
+		PIN:           "123456",
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Account Number should only contains numbers", resp.Message)
+}
+
+func TestCreateAccount_DuplicateAccountNumber(t *testing.T) {
+	svc := New()
+	_, resp := svc.CreateAccount(context.Background(), entity.Account{
+		Name:          "Richard Roe",
+		AccountNumber: "112233",
+		PIN:           "123456",
+	})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestCreateAccount_CanLogin(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, resp := svc.CreateAccount(ctx, entity.Account{
+		Name:          "Richard Roe",
+		AccountNumber: "112255",
+		PIN:           "123456",
+		Balance:       50,
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.AccountActive, acc.Status)
+	cards, _ := svc.AccountCards(ctx, "112255")
+	assert.Len(t, cards, 1)
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: cards[0].Number, PIN: "123456"})
+	assert.Nil(t, resp)
+}
+
+func TestResetPIN(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	assert.Nil(t, svc.ResetPIN(ctx, "112233", "654321"))
+	_, resp := svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.NotNil(t, resp)
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "654321"})
+	assert.Nil(t, resp)
+}
+
+func TestFrozenAccount_Rejected(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.FreezeAccount(ctx, "112233")
+	assert.Nil(t, resp)
+
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.Equal(t, ErrCodeAccountFrozen, resp.Code)
+	_, resp = svc.Withdraw(ctx, "112233", 10)
+	assert.Equal(t, ErrCodeAccountFrozen, resp.Code)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 10})
+	assert.Equal(t, ErrCodeAccountFrozen, resp.Code)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112244", ToAccountNumber: "112233", Amount: 10})
+	assert.Equal(t, ErrCodeDestinationUnavailable, resp.Code)
+
+	_, resp = svc.UnfreezeAccount(ctx, "112233")
+	assert.Nil(t, resp)
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.Nil(t, resp)
+}
+
+func TestCloseAccount(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.CloseAccount(ctx, "112233")
+	assert.Equal(t, "Account balance should be zero before closing", resp.Message)
+
+	svc.Withdraw(ctx, "112233", 100)
+	_, resp = svc.CloseAccount(ctx, "112233")
+	assert.Nil(t, resp)
+
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.Equal(t, ErrCodeAccountClosed, resp.Code)
+	_, resp = svc.UnfreezeAccount(ctx, "112233")
+	assert.Equal(t, ErrCodeAccountClosed, resp.Code)
+}
+
+func TestCloseAccount_NothingPending(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	svc.Withdraw(ctx, "112233", 100)
+	_, resp := svc.SetOverdraft(ctx, "112233", 100, 10)
+	assert.Nil(t, resp)
+
+	w, resp := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321"})
+	assert.Nil(t, resp)
+	_, resp = svc.CloseAccount(ctx, "112233")
+	assert.Equal(t, "Cancel the pending cardless withdrawals before closing", resp.Message)
+	svc.CancelCardlessWithdrawal(ctx, "112233", w.ID)
+
+	h, resp := svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 10, Reason: "Court order"})
+	assert.Nil(t, resp)
+	_, resp = svc.CloseAccount(ctx, "112233")
+	assert.Equal(t, "Release the active holds before closing", resp.Message)
+	svc.ReleaseHold(ctx, h.ID)
+
+	o, resp := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{ToAccountNumber: "112244", Amount: 30, Day: 20})
+	assert.Nil(t, resp)
+	_, resp = svc.CloseAccount(ctx, "112233")
+	assert.Equal(t, "Cancel the standing orders before closing", resp.Message)
+	svc.CancelStandingOrder(ctx, "112233", o.ID)
+
+	acc, resp := svc.CloseAccount(ctx, "112233")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.AccountClosed, acc.Status)
+}
+
+func TestNew_LoadsAndSavesRepository(t *testing.T) {
+	repo := repository.NewMemory()
+	repo.Save(&repository.Snapshot{Accounts: []entity.Account{
+		{Name: "Richard Roe", AccountNumber: "300001", PIN: "123456", Balance: 70, Status: entity.AccountActive},
+	}})
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	_, resp := svc.GetAccount(ctx, "112233")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	svc.Withdraw(ctx, "300001", 20)
+	snapshot, _ := repo.Load()
+	assert.Equal(t, float64(50), snapshot.Accounts[0].Balance)
+	assert.Len(t, snapshot.Transactions, 1)
+}
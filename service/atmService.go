@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/device"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+const (
+	defaultATMID         = "ATM001"
+	defaultCashInventory = 10000
+	// systemATMID is the ATM ID of the transactions the bank makes itself, like interest
+	systemATMID = "SYSTEM"
+)
+
+var defaultFastCashPresets = []float64{10, 50, 100}
+
+var atmMap = make(map[string]*entity.ATM)
+
+type atmContextKey struct{}
+
+// WithATM tells the service which ATM the request comes from
+func WithATM(ctx context.Context, atmID string) context.Context {
+	return context.WithValue(ctx, atmContextKey{}, atmID)
+}
+
+func atmID(ctx context.Context) string {
+	if id, ok := ctx.Value(atmContextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultATMID()
+}
+
+// DefaultATMID is the ATM used when the request does not tell which ATM it comes from
+func DefaultATMID() string {
+	if id := envLib.GetEnv("ATM_ID"); id != "" {
+		return id
+	}
+	return defaultATMID
+}
+
+func initATM() {
+	atm := &entity.ATM{
+		ID:              DefaultATMID(),
+		FastCashPresets: defaultFastCashPresets,
+		Cash:            defaultCashInventory,
+	}
+	if presets := envLib.GetEnv("ATM_FAST_CASH_PRESETS"); presets != "" {
+		atm.FastCashPresets = nil
+		for _, p := range strings.Split(presets, ",") {
+			amount, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil || amount <= 0 {
+				log.Printf("Invalid fast cash preset %s \n", p)
+				continue
+			}
+			atm.FastCashPresets = append(atm.FastCashPresets, amount)
+		}
+	}
+	if cash := envLib.GetEnv("ATM_CASH_INVENTORY"); cash != "" {
+		if amount, err := strconv.ParseFloat(cash, 64); err == nil {
+			atm.Cash = amount
+		} else {
+			log.Printf("Invalid ATM cash inventory %s \n", cash)
+		}
+	}
+	atmMap = map[string]*entity.ATM{atm.ID: atm}
+}
+
+// RegisterATM adds or replaces an ATM with its own presets and cash inventory
+func (s *Service) RegisterATM(ctx context.Context, atm entity.ATM) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if strings.Trim(atm.ID, " ") == "" {
+		return responseFormatter.New(http.StatusBadRequest, "ATM ID is required", true)
+	} else if atm.Cash < 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid cash inventory", true)
+	}
+	for _, p := range atm.FastCashPresets {
+		if resp := validateOtherAmount(p); resp != nil {
+			return responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Invalid fast cash preset $%0.f", p), true)
+		}
+	}
+	atmMap[atm.ID] = &atm
+	return nil
+}
+
+func (s *Service) getATM(ctx context.Context) (*entity.ATM, *responseFormatter.ResponseFormatter) {
+	atm := atmMap[atmID(ctx)]
+	if atm == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Unknown ATM", true)
+	}
+	return atm, nil
+}
+
+func (s *Service) FastCashPresets(ctx context.Context) ([]entity.FastCashPreset, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	atm, resp := s.getATM(ctx)
+	if resp != nil {
+		return nil, resp
+	}
+	presets := make([]entity.FastCashPreset, 0, len(atm.FastCashPresets))
+	for _, p := range atm.FastCashPresets {
+		presets = append(presets, entity.FastCashPreset{Amount: p, Available: atm.Cash >= p})
+	}
+	return presets, nil
+}
+
+func (s *Service) FastWithdraw(ctx context.Context, accountNumber string, preset float64) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	atm, resp := s.getATM(ctx)
+	if resp != nil {
+		return nil, resp
+	}
+	found := false
+	for _, p := range atm.FastCashPresets {
+		if p == preset {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid fast cash option", true)
+	}
+	return s.Withdraw(ctx, accountNumber, preset)
+}
+
+func (s *Service) OtherWithdraw(ctx context.Context, accountNumber string, withdrawAmount float64) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if resp := validateOtherAmount(withdrawAmount); resp != nil {
+		return nil, resp
+	}
+	return s.Withdraw(ctx, accountNumber, withdrawAmount)
+}
+
+// validateOtherAmount applies the rules of the "other amount" withdraw screen:
+// whole dollars, multiple of $10, between $10 and $1000
+func validateOtherAmount(amount float64) *responseFormatter.ResponseFormatter {
+	if amount != math.Trunc(amount) || int(amount)%10 != 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid ammount", true)
+	} else if amount < 10 {
+		return responseFormatter.New(http.StatusBadRequest, "Minimum amount to withdraw is $10", true)
+	} else if amount > 1000 {
+		return responseFormatter.New(http.StatusBadRequest, "Maximum amount to withdraw is $1000", true)
+	}
+	return nil
+}
+
+// dispense withdraws in two phases : the amount and its fee are held on the account while the cash is dispensed,
+// then only the cash that was handed out, and its fee, are posted and the hold is released.
+func (s *Service) dispense(ctx context.Context, atm *entity.ATM, acctNbr string, amount float64) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	charge := s.fee(accMap[acctNbr], fee.OnUs, fee.Withdraw, amount)
+	expiresAt := s.clock.Now().Add(pendingWithdrawExpiry)
+	hold := s.placeHold(acctNbr, entity.HoldPendingWithdraw, amount+charge, fmt.Sprintf("Withdraw at %s", atm.ID), &expiresAt)
+	dispensed, err := s.dispenser.Dispense(ctx, atm.ID, amount)
+	dispensed = math.Max(0, math.Min(dispensed, amount))
+	s.releaseHold(hold)
+	if dispensed == 0 {
+		s.save()
+		if err != nil {
+			log.Printf("Dispense of $%.2f at %s failed : %s \n", amount, atm.ID, err.Error())
+		}
+		return nil, responseFormatter.New(http.StatusServiceUnavailable,
+			"Cash could not be dispensed, your account has not been charged", true).
+			WithCode(ErrCodeDispenseFailed)
+	} else if err != nil {
+		log.Printf("Only $%.2f of $%.2f dispensed at %s : %s \n", dispensed, amount, atm.ID, err.Error())
+	}
+	if dispensed < amount {
+		charge = s.fee(accMap[acctNbr], fee.OnUs, fee.Withdraw, dispensed)
+	}
+
+	entry := ledger.WithdrawEntry(acctNbr, atm.ID, dispensed)
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return nil, resp
+	}
+	atm.Cash -= dispensed
+	trx := s.recordTransaction(ctx, entity.Transaction{
+		ID:            entry.TransactionID,
+		ATMID:         atm.ID,
+		AccountNumber: acctNbr,
+		Type:          entity.TransactionWithdraw,
+		Amount:        dispensed,
+	})
+	if resp := s.chargeFee(ctx, trx, charge); resp != nil {
+		return nil, resp
+	}
+	s.save()
+	accResp := s.toAccountResponse(accMap[acctNbr])
+	accResp.TransactionID = trx.ID
+	accResp.Dispensed = dispensed
+	accResp.Fee = charge
+	return accResp, nil
+}
+
+// SetDispenserFault makes every withdrawal at the ATM fail with fault, the dispenser has to be simulated
+func (s *Service) SetDispenserFault(ctx context.Context, atmID string, fault device.Fault) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	sim, ok := s.dispenser.(*device.Simulated)
+	if !ok {
+		return responseFormatter.New(http.StatusBadRequest, "Dispenser does not simulate faults", true)
+	} else if atmMap[atmID] == nil {
+		return responseFormatter.New(http.StatusNotFound, "ATM not found", true)
+	}
+	sim.SetFault(atmID, fault)
+	return nil
+}
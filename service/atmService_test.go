@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/device"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFastCashPresets_Default(t *testing.T) {
+	svc := New()
+	presets, resp := svc.FastCashPresets(context.Background())
+	assert.Nil(t, resp)
+	assert.Equal(t, []entity.FastCashPreset{
+		{Amount: 10, Available: true},
+		{Amount: 50, Available: true},
+		{Amount: 100, Available: true},
+	}, presets)
+}
+
+func TestFastCashPresets_RespectCashInventory(t *testing.T) {
+	svc := New()
+	svc.RegisterATM(context.Background(), entity.ATM{ID: "ATM002", FastCashPresets: []float64{20, 60}, Cash: 50})
+	presets, resp := svc.FastCashPresets(WithATM(context.Background(), "ATM002"))
+	assert.Nil(t, resp)
+	assert.Equal(t, []entity.FastCashPreset{
+		{Amount: 20, Available: true},
+		{Amount: 60, Available: false},
+	}, presets)
+}
+
+func TestFastWithdraw_UnknownPreset(t *testing.T) {
+	svc := New()
+	_, resp := svc.FastWithdraw(context.Background(), "112233", 20)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid fast cash option", resp.Message)
+}
+
+func TestFastWithdraw_Success(t *testing.T) {
+	svc := New()
+	acc, resp := svc.FastWithdraw(context.Background(), "112233", 50)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(50), acc.Balance)
+}
+
+func TestWithdraw_NotEnoughCashInATM(t *testing.T) {
+	svc := New()
+	ctx := WithATM(context.Background(), "ATM002")
+	svc.RegisterATM(ctx, entity.ATM{ID: "ATM002", FastCashPresets: []float64{10}, Cash: 30})
+	_, resp := svc.Withdraw(ctx, "112233", 50)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestOtherWithdraw_MinimumAmount(t *testing.T) {
+	svc := New()
+	_, resp := svc.OtherWithdraw(context.Background(), "112233", 0)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Minimum amount to withdraw is $10", resp.Message)
+}
+
+func TestOtherWithdraw_WholeDollarsOnly(t *testing.T) {
+	svc := New()
+	_, resp := svc.OtherWithdraw(context.Background(), "112233", 20.5)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid ammount", resp.Message)
+}
+
+func TestWithdraw_DispenserJamNeverLosesMoney(t *testing.T) {
+	svc := New()
+	ctx := device.WithFault(context.Background(), device.FaultJam)
+	cash := atmMap[DefaultATMID()].Cash
+
+	_, resp := svc.Withdraw(ctx, "112233", 50)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, ErrCodeDispenseFailed, resp.Code)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+	assert.Equal(t, cash, atmMap[DefaultATMID()].Cash)
+
+	trxs, _ := svc.Transactions(ctx, "112233")
+	assert.Empty(t, trxs)
+	holds, _ := svc.Holds(ctx, "112233", true)
+	assert.Len(t, holds, 1)
+	assert.Equal(t, entity.HoldPendingWithdraw, holds[0].Type)
+	assert.NotNil(t, holds[0].ReleasedAt)
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+}
+
+func TestWithdraw_PartialDispense(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	assert.Nil(t, svc.SetDispenserFault(ctx, DefaultATMID(), device.FaultPartial))
+	cash := atmMap[DefaultATMID()].Cash
+
+	acc, resp := svc.Withdraw(ctx, "112233", 80)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(40), acc.Dispensed)
+	assert.Equal(t, float64(60), acc.Balance)
+	assert.Equal(t, cash-40, atmMap[DefaultATMID()].Cash)
+
+	assert.Equal(t, float64(60), acc.AvailableBalance)
+
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, float64(40), trx.Amount)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+}
+
+func TestSetDispenserFault_UnknownATM(t *testing.T) {
+	svc := New()
+	resp := svc.SetDispenserFault(context.Background(), "ATM999", device.FaultJam)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
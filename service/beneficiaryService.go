@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/interbank"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+const (
+	// defaultBankCode is the code of this bank when BANK_CODE is not set
+	defaultBankCode   = "001"
+	maxNicknameLength = 20
+)
+
+var (
+	beneficiaryList []*entity.Beneficiary
+	beneficiaryMap  = make(map[string]*entity.Beneficiary)
+)
+
+func initBeneficiary() {
+	beneficiaryList = nil
+	beneficiaryMap = make(map[string]*entity.Beneficiary)
+}
+
+// BankCode is the code of the bank the accounts of this app belong to
+func BankCode() string {
+	if code := envLib.GetEnv("BANK_CODE"); code != "" {
+		return code
+	}
+	return defaultBankCode
+}
+
+// AddBeneficiary saves a destination account for acctNbr, the destination should be able to receive transfers.
+// The account of a beneficiary at another bank is looked up through the interbank switch.
+func (s *Service) AddBeneficiary(ctx context.Context, acctNbr string, beneficiary entity.Beneficiary) (*entity.Beneficiary, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	}
+	nickname := strings.TrimSpace(beneficiary.Nickname)
+	bankCode := beneficiary.BankCode
+	if bankCode == "" {
+		bankCode = BankCode()
+	}
+	switch {
+	case nickname == "":
+		return nil, responseFormatter.New(http.StatusBadRequest, "Nickname is required", true)
+	case utf8.RuneCountInString(nickname) > maxNicknameLength:
+		return nil, responseFormatter.New(http.StatusBadRequest, "Nickname should have at most 20 characters", true)
+	case beneficiary.ToAccountNumber == "":
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	case bankCode == BankCode() && beneficiary.ToAccountNumber == acctNbr:
+		return nil, responseFormatter.New(http.StatusBadRequest, "Cannot add your own account as a beneficiary", true)
+	}
+	var name string
+	if bankCode == BankCode() {
+		to := accMap[beneficiary.ToAccountNumber]
+		if to == nil {
+			return nil, responseFormatter.New(http.StatusNotFound, "Beneficiary account not found", true)
+		} else if to.Status == entity.AccountClosed || !accountTypeOf(to).CanReceiveTransfer {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Destination account cannot receive transfers", true).
+				WithCode(ErrCodeDestinationUnavailable)
+		}
+		name = to.Name
+	} else {
+		var err error
+		if name, err = s.network.Inquire(ctx, bankCode, beneficiary.ToAccountNumber); errors.Is(err, interbank.ErrAccountNotFound) {
+			return nil, responseFormatter.New(http.StatusNotFound, "Beneficiary account not found", true)
+		} else if err != nil {
+			return nil, interbankError(err)
+		}
+	}
+	for _, b := range beneficiaryList {
+		if b.AccountNumber == acctNbr && b.RemovedAt == nil && b.BankCode == bankCode && b.ToAccountNumber == beneficiary.ToAccountNumber {
+			return nil, responseFormatter.New(http.StatusConflict, "Account is already a beneficiary as "+b.Nickname, true)
+		}
+	}
+	b := &entity.Beneficiary{
+		ID:              entity.FormatBeneficiaryID(len(beneficiaryList) + 1),
+		AccountNumber:   acctNbr,
+		Nickname:        nickname,
+		ToAccountNumber: beneficiary.ToAccountNumber,
+		BankCode:        bankCode,
+		MaskedName:      maskName(name),
+		CreatedAt:       s.clock.Now(),
+	}
+	beneficiaryList = append(beneficiaryList, b)
+	beneficiaryMap[b.ID] = b
+	s.save()
+	return b, nil
+}
+
+func (s *Service) Beneficiaries(ctx context.Context, acctNbr string) []*entity.Beneficiary {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	beneficiaries := []*entity.Beneficiary{}
+	for _, b := range beneficiaryList {
+		if b.AccountNumber == acctNbr && b.RemovedAt == nil {
+			beneficiaries = append(beneficiaries, b)
+		}
+	}
+	return beneficiaries
+}
+
+func (s *Service) RemoveBeneficiary(ctx context.Context, acctNbr, beneficiaryID string) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	b, resp := findBeneficiary(acctNbr, beneficiaryID)
+	if resp != nil {
+		return resp
+	}
+	now := s.clock.Now()
+	b.RemovedAt = &now
+	s.save()
+	return nil
+}
+
+// findBeneficiary is the beneficiary beneficiaryID saved by acctNbr, the beneficiaries of other accounts are not found
+func findBeneficiary(acctNbr, beneficiaryID string) (*entity.Beneficiary, *responseFormatter.ResponseFormatter) {
+	b := beneficiaryMap[beneficiaryID]
+	if b == nil || b.AccountNumber != acctNbr || b.RemovedAt != nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Beneficiary not found", true)
+	}
+	return b, nil
+}
+
+// maskName keeps the first letter of every word of name, "Jane Doe" is "J*** D**"
+func maskName(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(r) + strings.Repeat("*", utf8.RuneCountInString(w[size:]))
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddBeneficiary_Validation(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	svc.CreateAccount(ctx, entity.Account{AccountNumber: "223344", PIN: "123456", Name: "Gone"})
+	svc.CloseAccount(ctx, "223344")
+	tests := []struct {
+		beneficiary entity.Beneficiary
+		message     string
+	}{
+		{entity.Beneficiary{ToAccountNumber: "112244"}, "Nickname is required"},
+		{entity.Beneficiary{Nickname: "Landlord of the flat downtown", ToAccountNumber: "112244"}, "Nickname should have at most 20 characters"},
+		{entity.Beneficiary{Nickname: "Me", ToAccountNumber: "112233"}, "Cannot add your own account as a beneficiary"},
+		{entity.Beneficiary{Nickname: "Nobody", ToAccountNumber: "999999"}, "Beneficiary account not found"},
+		{entity.Beneficiary{Nickname: "Other bank", ToAccountNumber: "112244", BankCode: "999"}, "Unknown bank code"},
+		{entity.Beneficiary{Nickname: "Gone", ToAccountNumber: "223344"}, "Destination account cannot receive transfers"},
+	}
+	for _, tt := range tests {
+		_, resp := svc.AddBeneficiary(ctx, "112233", tt.beneficiary)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, tt.message, resp.Message)
+		}
+	}
+}
+
+func TestBeneficiary_AddListRemove(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	b, resp := svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: " Jane ", ToAccountNumber: "112244"})
+	assert.Nil(t, resp)
+	assert.Equal(t, "Jane", b.Nickname)
+	assert.Equal(t, "J*** D**", b.MaskedName)
+	assert.Equal(t, BankCode(), b.BankCode)
+
+	_, resp = svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: "Sis", ToAccountNumber: "112244"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Len(t, svc.Beneficiaries(ctx, "112233"), 1)
+	assert.Empty(t, svc.Beneficiaries(ctx, "112244"))
+
+	assert.Equal(t, "Beneficiary not found", svc.RemoveBeneficiary(ctx, "112244", b.ID).Message)
+	assert.Nil(t, svc.RemoveBeneficiary(ctx, "112233", b.ID))
+	assert.Empty(t, svc.Beneficiaries(ctx, "112233"))
+	_, resp = svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: "Sis", ToAccountNumber: "112244"})
+	assert.Nil(t, resp)
+}
+
+func TestTransfer_ToBeneficiary(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	b, _ := svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: "Jane", ToAccountNumber: "112244"})
+
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112244", BeneficiaryID: b.ID, Amount: 10})
+	assert.Equal(t, "Beneficiary not found", resp.Message)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "999999", BeneficiaryID: b.ID, Amount: 10})
+	assert.Equal(t, "Destination account does not match the beneficiary", resp.Message)
+
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", BeneficiaryID: b.ID, Amount: 10})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(90), acc.Balance)
+	jane, _ := svc.BalanceCheck(ctx, "112244")
+	assert.Equal(t, float64(110), jane.Balance)
+}
+
+func TestMaskName(t *testing.T) {
+	assert.Equal(t, "J*** D**", maskName("Jane Doe"))
+	assert.Equal(t, "Z**", maskName(" Zoë "))
+	assert.Equal(t, "", maskName(""))
+}
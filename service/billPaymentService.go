@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/biller"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// pendingBillPaymentExpiry keeps a crash while the biller takes a payment from holding the money forever
+const pendingBillPaymentExpiry = 5 * time.Minute
+
+func (s *Service) Billers(ctx context.Context) []biller.Biller {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.billers.Billers()
+}
+
+// BillInquiry asks the biller for the bill of reference, to show the customer name and amount due before paying
+func (s *Service) BillInquiry(ctx context.Context, billerCode, reference string) (*biller.Bill, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	b, resp := s.findBiller(billerCode, reference)
+	if resp != nil {
+		return nil, resp
+	}
+	bill, err := s.billerClient.Inquire(ctx, b, reference)
+	if err != nil {
+		return nil, billerError(b, err)
+	}
+	return bill, nil
+}
+
+// PayBill debits the account and pays the bill to the biller. The money is held while the biller
+// takes the payment, the account is only charged when the biller accepts it.
+func (s *Service) PayBill(ctx context.Context, acctNbr string, payment entity.BillPayment) (*entity.BillPaymentReceipt, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if resp := checkAccountStatus(acc); resp != nil {
+		return nil, resp
+	} else if resp := checkBaseCurrency(acc, "Bill payment"); resp != nil {
+		return nil, resp
+	}
+	b, resp := s.findBiller(payment.BillerCode, payment.CustomerReference)
+	if resp != nil {
+		return nil, resp
+	}
+	bill, err := s.billerClient.Inquire(ctx, b, payment.CustomerReference)
+	if err != nil {
+		return nil, billerError(b, err)
+	}
+	amount := payment.Amount
+	if b.AmountType == biller.Fixed {
+		if amount == 0 {
+			amount = bill.AmountDue
+		} else if amount != bill.AmountDue {
+			return nil, responseFormatter.New(http.StatusBadRequest,
+				fmt.Sprintf("Amount should be the $%.2f due", bill.AmountDue), true)
+		}
+	} else if err := b.CheckAmount(amount); err != nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, capitalize(err.Error()), true)
+	}
+	if s.spendableBalance(acc) < amount {
+		return nil, insufficientBalance(acc, amount, 0)
+	} else if resp := s.checkMinimumBalance(acc, amount); resp != nil {
+		return nil, resp
+	}
+
+	expiresAt := s.clock.Now().Add(pendingBillPaymentExpiry)
+	hold := s.placeHold(acctNbr, entity.HoldPendingBillPayment, amount, "Bill payment to "+b.Name, &expiresAt)
+	billerRef, err := s.billerClient.Pay(ctx, b, payment.CustomerReference, amount, hold.ID)
+	s.releaseHold(hold)
+	if err != nil {
+		s.save()
+		log.Printf("Bill payment %s of $%.2f to %s failed : %s \n", hold.ID, amount, b.Code, err.Error())
+		return nil, responseFormatter.New(http.StatusBadGateway,
+			fmt.Sprintf("%s could not take the payment, your account has not been charged", b.Name), true).
+			WithCode(ErrCodeBillerUnavailable)
+	}
+	entry := ledger.BillPaymentEntry(acctNbr, b.Code, amount)
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return nil, resp
+	}
+	trx := s.recordTransaction(ctx, entity.Transaction{
+		ID:              entry.TransactionID,
+		AccountNumber:   acctNbr,
+		Type:            entity.TransactionBillPayment,
+		Amount:          amount,
+		ReferenceNumber: payment.CustomerReference,
+		BillerCode:      b.Code,
+		BillerReference: billerRef,
+	})
+	s.save()
+	return &entity.BillPaymentReceipt{
+		TransactionID:     trx.ID,
+		BillerCode:        b.Code,
+		BillerName:        b.Name,
+		CustomerReference: payment.CustomerReference,
+		CustomerName:      bill.CustomerName,
+		Amount:            amount,
+		BillerReference:   billerRef,
+		Time:              trx.Time,
+		Balance:           acc.Balance,
+		AvailableBalance:  s.availableBalance(acc),
+	}, nil
+}
+
+func (s *Service) findBiller(code, reference string) (biller.Biller, *responseFormatter.ResponseFormatter) {
+	b, ok := s.billers.Biller(code)
+	if strings.TrimSpace(code) == "" {
+		return b, responseFormatter.New(http.StatusBadRequest, "Biller code is required", true)
+	} else if !ok {
+		return b, responseFormatter.New(http.StatusNotFound, "Biller not found", true)
+	} else if !b.ValidReference(reference) {
+		return b, responseFormatter.New(http.StatusBadRequest, "Invalid "+b.ReferenceLabel, true)
+	}
+	return b, nil
+}
+
+func billerError(b biller.Biller, err error) *responseFormatter.ResponseFormatter {
+	switch {
+	case errors.Is(err, biller.ErrBillNotFound):
+		return responseFormatter.New(http.StatusNotFound, "No bill found for this "+b.ReferenceLabel, true)
+	case errors.Is(err, biller.ErrAlreadyPaid):
+		return responseFormatter.New(http.StatusConflict, "Bill is already paid", true)
+	}
+	log.Printf("Inquiry to %s failed : %s \n", b.Code, err.Error())
+	return responseFormatter.New(http.StatusBadGateway, b.Name+" is not available, please try again later", true).
+		WithCode(ErrCodeBillerUnavailable)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/biller"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayBill_Validation(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	tests := []struct {
+		payment entity.BillPayment
+		message string
+	}{
+		{entity.BillPayment{CustomerReference: "12345678901", Amount: 20}, "Biller code is required"},
+		{entity.BillPayment{BillerCode: "GAS", CustomerReference: "12345678901", Amount: 20}, "Biller not found"},
+		{entity.BillPayment{BillerCode: "ELEC", CustomerReference: "1234", Amount: 20}, "Invalid Meter Number"},
+		{entity.BillPayment{BillerCode: "ELEC", CustomerReference: "12345670000", Amount: 20}, "No bill found for this Meter Number"},
+		{entity.BillPayment{BillerCode: "ELEC", CustomerReference: "12345678901", Amount: 5}, "Minimum payment to City Electricity is $10"},
+		{entity.BillPayment{BillerCode: "ELEC", CustomerReference: "12345678901", Amount: 200}, "Insufficient balance $200"},
+		{entity.BillPayment{BillerCode: "WATER", CustomerReference: "12345678", Amount: 1}, "Amount should be the $70.00 due"},
+	}
+	for _, tt := range tests {
+		_, resp := svc.PayBill(ctx, "112233", tt.payment)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, tt.message, resp.Message)
+		}
+	}
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+}
+
+func TestPayBill_FixedAmountDue(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	bill, resp := svc.BillInquiry(ctx, "WATER", "12345678")
+	assert.Nil(t, resp)
+
+	receipt, resp := svc.PayBill(ctx, "112233", entity.BillPayment{BillerCode: "WATER", CustomerReference: "12345678"})
+	assert.Nil(t, resp)
+	assert.Equal(t, bill.AmountDue, receipt.Amount)
+	assert.Equal(t, "City Water", receipt.BillerName)
+	assert.Equal(t, "Customer 5678", receipt.CustomerName)
+	assert.Equal(t, "WATER00000001", receipt.BillerReference)
+	assert.Equal(t, 100-bill.AmountDue, receipt.Balance)
+
+	trx, _ := svc.GetTransaction(ctx, receipt.TransactionID)
+	assert.Equal(t, entity.TransactionBillPayment, trx.Type)
+	assert.Equal(t, "12345678", trx.ReferenceNumber)
+	printed, _ := svc.IssueReceipt(ctx, receipt.TransactionID)
+	assert.Equal(t, "City Water", printed.Biller)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	_, resp = svc.PayBill(ctx, "112233", entity.BillPayment{BillerCode: "WATER", CustomerReference: "12345678"})
+	assert.Equal(t, "Bill is already paid", resp.Message)
+}
+
+func TestPayBill_BillerDown(t *testing.T) {
+	stub := biller.NewStub()
+	svc := New(WithBillerClient(stub))
+	ctx := context.Background()
+	stub.SetDown(true)
+	_, resp := svc.PayBill(ctx, "112233", entity.BillPayment{BillerCode: "ELEC", CustomerReference: "12345678901", Amount: 50})
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, ErrCodeBillerUnavailable, resp.Code)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+}
+
+type decliningBiller struct{ *biller.Stub }
+
+func (decliningBiller) Pay(ctx context.Context, b biller.Biller, reference string, amount float64, paymentID string) (string, error) {
+	return "", biller.ErrUnavailable
+}
+
+func TestPayBill_DeclinedPaymentIsNotCharged(t *testing.T) {
+	svc := New(WithBillerClient(decliningBiller{biller.NewStub()}))
+	ctx := context.Background()
+	_, resp := svc.PayBill(ctx, "112233", entity.BillPayment{BillerCode: "ELEC", CustomerReference: "12345678901", Amount: 50})
+	assert.Equal(t, "City Electricity could not take the payment, your account has not been charged", resp.Message)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+	trxs, _ := svc.Transactions(ctx, "112233")
+	assert.Empty(t, trxs)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/luhn"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+const (
+	// cardBIN starts the number of every card issued by the simulator
+	cardBIN        = "400000"
+	cardValidYears = 4
+)
+
+var (
+	cardList []*entity.Card
+	cardMap  = make(map[string]*entity.Card)
+)
+
+func initCard() {
+	cardList = nil
+	cardMap = make(map[string]*entity.Card)
+}
+
+// issueCard adds a card for acctNbrs, valid until the end of the month cardValidYears from now
+func (s *Service) issueCard(pin string, acctNbrs ...string) *entity.Card {
+	now := s.clock.Now()
+	expiry := now.AddDate(cardValidYears, 0, 0)
+	c := &entity.Card{
+		Number:         luhn.Append(fmt.Sprintf("%s%09d", cardBIN, len(cardList)+1)),
+		PIN:            pin,
+		Status:         entity.CardActive,
+		AccountNumbers: acctNbrs,
+		ExpiresAt:      time.Date(expiry.Year(), expiry.Month()+1, 1, 0, 0, 0, 0, time.Local),
+		IssuedAt:       now,
+	}
+	cardList = append(cardList, c)
+	cardMap[c.Number] = c
+	return c
+}
+
+// issueMissingCards gives a card, with the account PIN, to the accounts no card can access,
+// like the accounts saved before there were cards
+func (s *Service) issueMissingCards() {
+	linked := make(map[string]bool)
+	for _, c := range cardList {
+		for _, acctNbr := range c.AccountNumbers {
+			linked[acctNbr] = true
+		}
+	}
+	for _, acc := range sortedAccounts() {
+		if !linked[acc.AccountNumber] && acc.Status != entity.AccountClosed {
+			s.issueCard(acc.PIN, acc.AccountNumber)
+		}
+	}
+}
+
+func cardsOf(acctNbr string) []*entity.Card {
+	var cards []*entity.Card
+	for _, c := range cardList {
+		for _, n := range c.AccountNumbers {
+			if n == acctNbr {
+				cards = append(cards, c)
+				break
+			}
+		}
+	}
+	return cards
+}
+
+// validateCardLogin checks the format of a card number and PIN
+func validateCardLogin(login entity.CardLogin) *responseFormatter.ResponseFormatter {
+	if strings.Trim(login.CardNumber, " ") == "" {
+		return responseFormatter.New(http.StatusBadRequest, "Card Number is required", true)
+	} else if strings.Trim(login.PIN, " ") == "" {
+		return responseFormatter.New(http.StatusBadRequest, "PIN is required", true)
+	} else if len(login.CardNumber) != 16 {
+		return responseFormatter.New(http.StatusBadRequest, "Card Number should have 16 digits length", true)
+	} else if len(login.PIN) < 6 {
+		return responseFormatter.New(http.StatusBadRequest, "PIN should have 6 digits length", true)
+	} else if _, err := strconv.ParseUint(login.CardNumber, 10, 64); err != nil {
+		return responseFormatter.New(http.StatusBadRequest, "Card Number should only contains numbers", true)
+	} else if _, err := strconv.Atoi(login.PIN); err != nil {
+		return responseFormatter.New(http.StatusBadRequest, "PIN should only contains numbers", true)
+	} else if !luhn.Valid(login.CardNumber) {
+		return responseFormatter.New(http.StatusBadRequest, "Invalid Card Number", true)
+	}
+	return nil
+}
+
+// checkCardStatus rejects blocked and expired cards
+func (s *Service) checkCardStatus(card *entity.Card) *responseFormatter.ResponseFormatter {
+	if card.Status == entity.CardBlocked {
+		return responseFormatter.New(http.StatusForbidden, "Card is blocked", true).WithCode(ErrCodeCardBlocked)
+	} else if card.Expired(s.clock.Now()) {
+		return responseFormatter.New(http.StatusForbidden, "Card is expired", true).WithCode(ErrCodeCardExpired)
+	}
+	return nil
+}
+
+// Accounts lists the accounts the card the customer logged in with can access
+func (s *Service) Accounts(ctx context.Context, cardNumber string) ([]*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	card := cardMap[cardNumber]
+	if card == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid card", true)
+	} else if resp := s.checkCardStatus(card); resp != nil {
+		return nil, resp
+	}
+	accounts := make([]*entity.AccountResponse, 0, len(card.AccountNumbers))
+	for _, acctNbr := range card.AccountNumbers {
+		if acc := accMap[acctNbr]; acc != nil {
+			accounts = append(accounts, s.toAccountResponse(acc))
+		}
+	}
+	return accounts, nil
+}
+
+// SelectAccount checks that the card the customer logged in with can operate on selected
+func (s *Service) SelectAccount(ctx context.Context, cardNumber, selected string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	accounts, resp := s.Accounts(ctx, cardNumber)
+	if resp != nil {
+		return nil, resp
+	}
+	for _, acc := range accounts {
+		if acc.AccountNumber == selected {
+			if resp := checkAccountStatus(accMap[selected]); resp != nil {
+				return nil, resp
+			}
+			return acc, nil
+		}
+	}
+	return nil, responseFormatter.New(http.StatusForbidden, "Account is not linked to this card", true)
+}
+
+// IssueCard gives a new card to card.AccountNumbers, the first account is where its sessions start
+func (s *Service) IssueCard(ctx context.Context, card entity.Card) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if resp := validateCardAccounts(card.AccountNumbers); resp != nil {
+		return nil, resp
+	} else if strings.Trim(card.PIN, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN is required", true)
+	} else if len(card.PIN) < 6 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN should have 6 digits length", true)
+	} else if _, err := strconv.Atoi(card.PIN); err != nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN should only contains numbers", true)
+	} else if !card.ExpiresAt.IsZero() && !card.ExpiresAt.After(s.clock.Now()) {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Card expiry should be in the future", true)
+	}
+	c := s.issueCard(card.PIN, card.AccountNumbers...)
+	if !card.ExpiresAt.IsZero() {
+		c.ExpiresAt = card.ExpiresAt
+	}
+	s.save()
+	return c.ToCardResponse(), nil
+}
+
+func validateCardAccounts(acctNbrs []string) *responseFormatter.ResponseFormatter {
+	if len(acctNbrs) == 0 {
+		return responseFormatter.New(http.StatusBadRequest, "At least one account is required", true)
+	}
+	seen := make(map[string]bool)
+	for _, acctNbr := range acctNbrs {
+		acc, resp := findAccount(acctNbr)
+		if resp != nil {
+			return resp
+		} else if acc.Status == entity.AccountClosed {
+			return responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Account %s is closed", acctNbr), true).
+				WithCode(ErrCodeAccountClosed)
+		} else if seen[acctNbr] {
+			return responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Account %s is listed twice", acctNbr), true)
+		}
+		seen[acctNbr] = true
+	}
+	return nil
+}
+
+func (s *Service) GetCard(ctx context.Context, cardNumber string) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	card, resp := findCard(cardNumber)
+	if resp != nil {
+		return nil, resp
+	}
+	return card.ToCardResponse(), nil
+}
+
+func (s *Service) AccountCards(ctx context.Context, acctNbr string) ([]*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	}
+	cards := []*entity.CardResponse{}
+	for _, c := range cardsOf(acctNbr) {
+		cards = append(cards, c.ToCardResponse())
+	}
+	return cards, nil
+}
+
+// LinkCardAccounts replaces the accounts a card can access
+func (s *Service) LinkCardAccounts(ctx context.Context, cardNumber string, acctNbrs []string) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	card, resp := findCard(cardNumber)
+	if resp != nil {
+		return nil, resp
+	} else if resp := validateCardAccounts(acctNbrs); resp != nil {
+		return nil, resp
+	}
+	card.AccountNumbers = append([]string(nil), acctNbrs...)
+	s.save()
+	return card.ToCardResponse(), nil
+}
+
+func (s *Service) BlockCard(ctx context.Context, cardNumber string) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.setCardStatus(cardNumber, entity.CardActive, entity.CardBlocked)
+}
+
+func (s *Service) UnblockCard(ctx context.Context, cardNumber string) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.setCardStatus(cardNumber, entity.CardBlocked, entity.CardActive)
+}
+
+func (s *Service) setCardStatus(cardNumber string, from, to entity.CardStatus) (*entity.CardResponse, *responseFormatter.ResponseFormatter) {
+	card, resp := findCard(cardNumber)
+	if resp != nil {
+		return nil, resp
+	} else if card.Status != from {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Card is already "+strings.ToLower(string(to)), true)
+	}
+	card.Status = to
+	s.save()
+	return card.ToCardResponse(), nil
+}
+
+func findCard(cardNumber string) (*entity.Card, *responseFormatter.ResponseFormatter) {
+	if strings.Trim(cardNumber, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Card Number is required", true)
+	} else if cardMap[cardNumber] == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Card not found", true)
+	}
+	return cardMap[cardNumber], nil
+}
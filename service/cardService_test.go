@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/luhn"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCard_IssuedToEveryAccount(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	cards, resp := svc.AccountCards(ctx, "112233")
+	assert.Nil(t, resp)
+	assert.Len(t, cards, 1)
+	assert.Equal(t, johnCard, cards[0].Number)
+	assert.True(t, luhn.Valid(cards[0].Number))
+	assert.Equal(t, entity.CardActive, cards[0].Status)
+	assert.True(t, cards[0].ExpiresAt.After(time.Now().AddDate(cardValidYears, 0, 0)))
+}
+
+func TestCard_MultipleAccounts(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.CreateAccount(ctx, entity.Account{
+		Name: "John Doe", AccountNumber: "112299", PIN: "111111", Balance: 500, Type: entity.AccountSavings,
+	})
+	assert.Nil(t, resp)
+	card, resp := svc.IssueCard(ctx, entity.Card{PIN: "222222", AccountNumbers: []string{"112233", "112299"}})
+	assert.Nil(t, resp)
+
+	acc, resp := svc.PINValidation(ctx, entity.CardLogin{CardNumber: card.Number, PIN: "222222"})
+	assert.Nil(t, resp)
+	assert.Equal(t, "112233", acc.AccountNumber)
+	accounts, resp := svc.Accounts(ctx, card.Number)
+	assert.Nil(t, resp)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, entity.AccountSavings, accounts[1].Type)
+
+	acc, resp = svc.SelectAccount(ctx, card.Number, "112299")
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(500), acc.Balance)
+	_, resp = svc.SelectAccount(ctx, card.Number, "112244")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	cards, _ := svc.AccountCards(ctx, "112233")
+	assert.Len(t, cards, 2)
+}
+
+func TestCard_SkipsFrozenAccount(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	card, _ := svc.IssueCard(ctx, entity.Card{PIN: "222222", AccountNumbers: []string{"112233", "112244"}})
+	svc.FreezeAccount(ctx, "112233")
+
+	acc, resp := svc.PINValidation(ctx, entity.CardLogin{CardNumber: card.Number, PIN: "222222"})
+	assert.Nil(t, resp)
+	assert.Equal(t, "112244", acc.AccountNumber)
+	_, resp = svc.SelectAccount(ctx, card.Number, "112233")
+	assert.Equal(t, ErrCodeAccountFrozen, resp.Code)
+}
+
+func TestCard_BlockedAndExpiredRejected(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.BlockCard(ctx, johnCard)
+	assert.Nil(t, resp)
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, ErrCodeCardBlocked, resp.Code)
+	_, resp = svc.BlockCard(ctx, johnCard)
+	assert.Equal(t, "Card is already blocked", resp.Message)
+	_, resp = svc.UnblockCard(ctx, johnCard)
+	assert.Nil(t, resp)
+
+	cardMap[janeCard].ExpiresAt = time.Now().Add(-time.Second)
+	_, resp = svc.PINValidation(ctx, entity.CardLogin{CardNumber: janeCard, PIN: "932012"})
+	assert.Equal(t, ErrCodeCardExpired, resp.Code)
+	_, resp = svc.Accounts(ctx, janeCard)
+	assert.Equal(t, ErrCodeCardExpired, resp.Code)
+}
+
+func TestIssueCard_Validation(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.IssueCard(ctx, entity.Card{PIN: "222222"})
+	assert.Equal(t, "At least one account is required", resp.Message)
+	_, resp = svc.IssueCard(ctx, entity.Card{PIN: "222222", AccountNumbers: []string{"112233", "112233"}})
+	assert.Equal(t, "Account 112233 is listed twice", resp.Message)
+	_, resp = svc.IssueCard(ctx, entity.Card{PIN: "222222", AccountNumbers: []string{"999999"}})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_, resp = svc.IssueCard(ctx, entity.Card{PIN: "22a222", AccountNumbers: []string{"112233"}})
+	assert.Equal(t, "PIN should only contains numbers", resp.Message)
+	_, resp = svc.IssueCard(ctx, entity.Card{PIN: "222222", AccountNumbers: []string{"112233"}, ExpiresAt: time.Now()})
+	assert.Equal(t, "Card expiry should be in the future", resp.Message)
+
+	card, resp := svc.LinkCardAccounts(ctx, johnCard, []string{"112244", "112233"})
+	assert.Nil(t, resp)
+	assert.Equal(t, []string{"112244", "112233"}, card.AccountNumbers)
+}
+
+func TestCard_IssuedToSavedAccountsWithoutCard(t *testing.T) {
+	repo := repository.NewMemory()
+	repo.Save(&repository.Snapshot{Accounts: []entity.Account{
+		{Name: "Richard Roe", AccountNumber: "300001", PIN: "123456", Balance: 70, Status: entity.AccountActive},
+	}})
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	cards, _ := svc.AccountCards(ctx, "300001")
+	assert.Len(t, cards, 1)
+	_, resp := svc.PINValidation(ctx, entity.CardLogin{CardNumber: cards[0].Number, PIN: "123456"})
+	assert.Nil(t, resp)
+
+	svc.BlockCard(ctx, cards[0].Number)
+	saved, _ := repo.Load()
+	assert.Len(t, saved.Cards, 1)
+	svc = New(WithRepository(repo))
+	card, _ := svc.GetCard(ctx, cards[0].Number)
+	assert.Equal(t, entity.CardBlocked, card.Status)
+	all, _ := svc.AccountCards(ctx, "300001")
+	assert.Len(t, all, 1)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+const (
+	// defaultCardlessExpiry is how long a code can be used when neither the request nor CARDLESS_EXPIRY tell
+	defaultCardlessExpiry = 30 * time.Minute
+	maxCardlessExpiry     = 24 * time.Hour
+	cardlessCodeLength    = 8
+	cardlessPINLength     = 4
+	// maxCardlessAttempts wrong PINs block the code, so it cannot be guessed at the ATM
+	maxCardlessAttempts = 3
+)
+
+var (
+	cardlessList []*entity.CardlessWithdrawal
+	cardlessMap  = make(map[string]*entity.CardlessWithdrawal)
+	// cardlessCodeMap finds a withdrawal by its code, a code is only unique among the pending withdrawals
+	cardlessCodeMap = make(map[string]*entity.CardlessWithdrawal)
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+)
+
+func initCardless() {
+	cardlessList = nil
+	cardlessMap = make(map[string]*entity.CardlessWithdrawal)
+	cardlessCodeMap = make(map[string]*entity.CardlessWithdrawal)
+}
+
+func addCardless(w *entity.CardlessWithdrawal) {
+	cardlessList = append(cardlessList, w)
+	cardlessMap[w.ID] = w
+	cardlessCodeMap[w.Code] = w
+}
+
+func cardlessExpiry(minutes int) (time.Duration, *responseFormatter.ResponseFormatter) {
+	if minutes < 0 {
+		return 0, responseFormatter.New(http.StatusBadRequest, "Expiry should be in the future", true)
+	} else if minutes > 0 {
+		if expiry := time.Duration(minutes) * time.Minute; expiry <= maxCardlessExpiry {
+			return expiry, nil
+		}
+		return 0, responseFormatter.New(http.StatusBadRequest, "Code cannot be valid for more than 24 hours", true)
+	}
+	if value := envLib.GetEnv("CARDLESS_EXPIRY"); value != "" {
+		expiry, err := time.ParseDuration(value)
+		if err == nil && expiry > 0 && expiry <= maxCardlessExpiry {
+			return expiry, nil
+		}
+		log.Printf("Invalid CARDLESS_EXPIRY %q, using %s \n", value, defaultCardlessExpiry)
+	}
+	return defaultCardlessExpiry, nil
+}
+
+// newCardlessCode draws a random code no pending withdrawal uses
+func (s *Service) newCardlessCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < cardlessCodeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	for {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code := fmt.Sprintf("%0*d", cardlessCodeLength, n)
+		if w := cardlessCodeMap[code]; w == nil || s.refreshCardless(w).Status != entity.CardlessPending {
+			return code, nil
+		}
+	}
+}
+
+// refreshCardless marks a pending withdrawal past its expiry as expired, its hold expires with it
+func (s *Service) refreshCardless(w *entity.CardlessWithdrawal) *entity.CardlessWithdrawal {
+	if w.Status == entity.CardlessPending && !s.clock.Now().Before(w.ExpiresAt) {
+		w.Status = entity.CardlessExpired
+	}
+	return w
+}
+
+// withoutPIN is w as shown to the customer, the secondary PIN is never sent back
+func (s *Service) withoutPIN(w *entity.CardlessWithdrawal) *entity.CardlessWithdrawal {
+	c := *s.refreshCardless(w)
+	c.PIN = ""
+	return &c
+}
+
+// CreateCardlessWithdrawal stages a withdrawal of acctNbr that can be collected at any ATM with the returned
+// code and the secondary PIN of the request. The amount and its fee are held until the code is redeemed,
+// cancelled or expires, the hold expires with the code.
+func (s *Service) CreateCardlessWithdrawal(ctx context.Context, acctNbr string, req entity.CardlessWithdrawalRequest) (*entity.CardlessWithdrawal, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if resp := checkAccountStatus(acc); resp != nil {
+		return nil, resp
+	} else if resp := checkBaseCurrency(acc, "Cardless withdrawal"); resp != nil {
+		return nil, resp
+	} else if resp := checkWithdrawAmount(req.Amount); resp != nil {
+		return nil, resp
+	}
+	if strings.Trim(req.PIN, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN is required", true)
+	} else if len(req.PIN) != cardlessPINLength {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN should have 4 digits length", true)
+	} else if _, err := strconv.Atoi(req.PIN); err != nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN should only contains numbers", true)
+	} else if req.RecipientPhone != "" && !phonePattern.MatchString(req.RecipientPhone) {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Recipient phone should have 8 to 15 digits", true)
+	}
+	expiry, resp := cardlessExpiry(req.ExpiresInMinutes)
+	if resp != nil {
+		return nil, resp
+	}
+	charge := s.fee(acc, fee.OnUs, fee.Withdraw, req.Amount)
+	if s.spendableBalance(acc) < req.Amount+charge {
+		return nil, insufficientBalance(acc, req.Amount, charge)
+	} else if resp := s.checkWithdrawRules(acc, req.Amount, charge); resp != nil {
+		return nil, resp
+	} else if resp := s.checkStepUp(ctx, acc, s.aboveStepUpThreshold(acc, req.Amount)); resp != nil {
+		return nil, resp
+	}
+	code, err := s.newCardlessCode()
+	if err != nil {
+		return nil, responseFormatter.New(http.StatusInternalServerError, "Failed generating code : "+err.Error(), true)
+	}
+
+	now := s.clock.Now()
+	w := &entity.CardlessWithdrawal{
+		ID:             entity.FormatCardlessWithdrawalID(len(cardlessList) + 1),
+		AccountNumber:  acctNbr,
+		Amount:         req.Amount,
+		Code:           code,
+		PIN:            req.PIN,
+		RecipientPhone: req.RecipientPhone,
+		Status:         entity.CardlessPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(expiry),
+	}
+	hold := s.placeHold(acctNbr, entity.HoldCardlessWithdraw, req.Amount+charge, "Cardless withdrawal "+w.ID, &w.ExpiresAt)
+	w.HoldID = hold.ID
+	addCardless(w)
+	if w.RecipientPhone != "" {
+		// there is no SMS gateway in the simulation, the message is only logged
+		log.Printf("SMS to %s : your cash withdrawal code is %s, valid until %s \n",
+			w.RecipientPhone, w.Code, w.ExpiresAt.Format("2006-01-02 15:04"))
+		s.notify(acctNbr, fmt.Sprintf("The code of cardless withdrawal %s of %s has been sent to %s",
+			w.ID, money(acc, w.Amount), w.RecipientPhone))
+	}
+	s.save()
+	return s.withoutPIN(w), nil
+}
+
+// CardlessWithdrawals lists the cardless withdrawals of an account
+func (s *Service) CardlessWithdrawals(ctx context.Context, acctNbr string) []*entity.CardlessWithdrawal {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	withdrawals := []*entity.CardlessWithdrawal{}
+	for _, w := range cardlessList {
+		if w.AccountNumber == acctNbr {
+			withdrawals = append(withdrawals, s.withoutPIN(w))
+		}
+	}
+	return withdrawals
+}
+
+// CancelCardlessWithdrawal voids the code of a pending withdrawal and releases its hold
+func (s *Service) CancelCardlessWithdrawal(ctx context.Context, acctNbr, id string) (*entity.CardlessWithdrawal, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	w := cardlessMap[id]
+	if w == nil || w.AccountNumber != acctNbr {
+		return nil, responseFormatter.New(http.StatusNotFound, "Cardless withdrawal not found", true)
+	} else if s.refreshCardless(w).Status != entity.CardlessPending {
+		return nil, responseFormatter.New(http.StatusConflict, "Cardless withdrawal is already redeemed, cancelled or expired", true)
+	}
+	now := s.clock.Now()
+	w.Status = entity.CardlessCancelled
+	w.CancelledAt = &now
+	s.releaseHold(holdMap[w.HoldID])
+	s.save()
+	return s.withoutPIN(w), nil
+}
+
+// RedeemCardlessWithdrawal pays out a cardless withdrawal at the ATM of ctx, through the same path as a withdrawal
+// with a card. When the withdrawal fails, like when the ATM is out of cash, the code can still be used.
+// Whoever collects the cash may not be the account holder, so the balance and account number are not shown.
+func (s *Service) RedeemCardlessWithdrawal(ctx context.Context, redemption entity.CardlessRedemption) (*entity.CardlessWithdrawal, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if strings.Trim(redemption.Code, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Code is required", true)
+	} else if strings.Trim(redemption.PIN, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "PIN is required", true)
+	}
+	w := cardlessCodeMap[strings.TrimSpace(redemption.Code)]
+	if w == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Code/PIN", true)
+	}
+	s.refreshCardless(w)
+	if w.PIN != redemption.PIN {
+		if w.Status != entity.CardlessPending {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Code/PIN", true)
+		}
+		w.FailedAttempts++
+		if w.FailedAttempts < maxCardlessAttempts {
+			s.save()
+			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Code/PIN", true)
+		}
+		w.Status = entity.CardlessBlocked
+		s.releaseHold(holdMap[w.HoldID])
+		s.notify(w.AccountNumber, fmt.Sprintf("Cardless withdrawal %s has been blocked after %d wrong PINs", w.ID, maxCardlessAttempts))
+		s.save()
+	}
+	switch w.Status {
+	case entity.CardlessRedeemed:
+		return nil, responseFormatter.New(http.StatusConflict, "Code was already used", true)
+	case entity.CardlessCancelled:
+		return nil, responseFormatter.New(http.StatusConflict, "Code has been cancelled", true)
+	case entity.CardlessExpired:
+		return nil, responseFormatter.New(http.StatusConflict, "Code has expired", true).WithCode(ErrCodeCardlessExpired)
+	case entity.CardlessBlocked:
+		return nil, responseFormatter.New(http.StatusForbidden, "Code is blocked after too many wrong PINs", true).
+			WithCode(ErrCodeCardlessBlocked)
+	}
+
+	hold := holdMap[w.HoldID]
+	s.releaseHold(hold)
+	acc, resp := s.Withdraw(preAuthorized(ctx), w.AccountNumber, w.Amount)
+	if resp != nil {
+		hold.ReleasedAt = nil
+		s.save()
+		return nil, resp
+	}
+	now := s.clock.Now()
+	w.Status = entity.CardlessRedeemed
+	w.RedeemedAt = &now
+	w.ATMID = atmID(ctx)
+	w.TransactionID = acc.TransactionID
+	w.Dispensed = acc.Dispensed
+	s.notify(w.AccountNumber, fmt.Sprintf("Cardless withdrawal %s of %s has been collected at %s",
+		w.ID, money(accMap[w.AccountNumber], w.Dispensed), w.ATMID))
+	s.save()
+	redeemed := s.withoutPIN(w)
+	redeemed.AccountNumber = maskAccountNumber(w.AccountNumber)
+	return redeemed, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardlessWithdrawal_Redeem(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	w, resp := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321", RecipientPhone: "+6581234567"})
+	assert.Nil(t, resp)
+	assert.Len(t, w.Code, cardlessCodeLength)
+	assert.Empty(t, w.PIN)
+	assert.Equal(t, entity.CardlessPending, w.Status)
+	assert.Equal(t, monday.Add(defaultCardlessExpiry), w.ExpiresAt)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+	assert.Equal(t, float64(60), acc.AvailableBalance)
+
+	redeemed, resp := svc.RedeemCardlessWithdrawal(WithATM(ctx, "ATM001"), entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(40), redeemed.Dispensed)
+	assert.Equal(t, maskAccountNumber("112233"), redeemed.AccountNumber)
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(60), acc.Balance)
+	assert.Equal(t, float64(60), acc.AvailableBalance)
+
+	w = svc.CardlessWithdrawals(ctx, "112233")[0]
+	assert.Equal(t, entity.CardlessRedeemed, w.Status)
+	assert.Equal(t, redeemed.TransactionID, w.TransactionID)
+	assert.Equal(t, "ATM001", w.ATMID)
+	assert.Len(t, svc.Notifications(ctx, "112233"), 2)
+
+	_, resp = svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "Code was already used", resp.Message)
+}
+
+func TestCardlessWithdrawal_RedeemAfterRestart(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := New(WithClock(clock.NewFake(monday)), WithRepository(repo))
+	ctx := context.Background()
+	w, resp := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321"})
+	assert.Nil(t, resp)
+
+	svc = New(WithClock(clock.NewFake(monday.Add(time.Minute))), WithRepository(repo))
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(60), acc.AvailableBalance)
+	redeemed, resp := svc.RedeemCardlessWithdrawal(WithATM(ctx, "ATM001"), entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(40), redeemed.Dispensed)
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(60), acc.Balance)
+	assert.Equal(t, float64(60), acc.AvailableBalance)
+}
+
+func TestCardlessWithdrawal_Validation(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	tests := []struct {
+		req     entity.CardlessWithdrawalRequest
+		message string
+	}{
+		{entity.CardlessWithdrawalRequest{Amount: 45, PIN: "4321"}, "Invalid ammount"},
+		{entity.CardlessWithdrawalRequest{Amount: 40}, "PIN is required"},
+		{entity.CardlessWithdrawalRequest{Amount: 40, PIN: "43210"}, "PIN should have 4 digits length"},
+		{entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321", RecipientPhone: "12-34"}, "Recipient phone should have 8 to 15 digits"},
+		{entity.CardlessWithdrawalRequest{Amount: 40, PIN: "4321", ExpiresInMinutes: 1441}, "Code cannot be valid for more than 24 hours"},
+		{entity.CardlessWithdrawalRequest{Amount: 200, PIN: "4321"}, "Insufficient balance $200"},
+	}
+	for _, tt := range tests {
+		_, resp := svc.CreateCardlessWithdrawal(ctx, "112233", tt.req)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, tt.message, resp.Message)
+		}
+	}
+
+	svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 80, PIN: "4321"})
+	_, resp := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 30, PIN: "4321"})
+	assert.Equal(t, ErrCodeInsufficientBalance, resp.Code)
+}
+
+func TestCardlessWithdrawal_ExpiresAndReleasesHold(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	w, _ := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 50, PIN: "4321", ExpiresInMinutes: 10})
+	c.Advance(10 * time.Minute)
+
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+	_, resp := svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Equal(t, ErrCodeCardlessExpired, resp.Code)
+	assert.Equal(t, entity.CardlessExpired, svc.CardlessWithdrawals(ctx, "112233")[0].Status)
+	_, resp = svc.CancelCardlessWithdrawal(ctx, "112233", w.ID)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestCardlessWithdrawal_WrongPINBlocksCode(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	w, _ := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 50, PIN: "4321"})
+	for i := 1; i < maxCardlessAttempts; i++ {
+		_, resp := svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "1111"})
+		assert.Equal(t, "Invalid Code/PIN", resp.Message)
+	}
+	_, resp := svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "1111"})
+	assert.Equal(t, ErrCodeCardlessBlocked, resp.Code)
+	_, resp = svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Equal(t, ErrCodeCardlessBlocked, resp.Code)
+
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+}
+
+func TestCardlessWithdrawal_FailedDispenseKeepsCode(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	w, _ := svc.CreateCardlessWithdrawal(ctx, "112233", entity.CardlessWithdrawalRequest{Amount: 50, PIN: "4321"})
+	_, resp := svc.RedeemCardlessWithdrawal(WithATM(ctx, "ATM999"), entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Equal(t, "Unknown ATM", resp.Message)
+
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(50), acc.AvailableBalance)
+	_, resp = svc.CancelCardlessWithdrawal(ctx, "112244", w.ID)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	w, resp = svc.CancelCardlessWithdrawal(ctx, "112233", w.ID)
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.CardlessCancelled, w.Status)
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+)
+
+func (s *Service) Clock() clock.Clock {
+	return s.clock
+}
+
+// TravelTo moves the clock forward to t and runs the batch jobs of the days it skipped.
+// Only an adjustable clock can travel, and never backwards : what happened cannot be undone.
+func (s *Service) TravelTo(ctx context.Context, t time.Time) ([]scheduler.Run, *responseFormatter.ResponseFormatter) {
+	if resp := s.moveClock(ctx, t); resp != nil {
+		return nil, resp
+	}
+	return s.RunJobs(ctx), nil
+}
+
+// moveClock sets the clock under the lock, so that no operation sees the time jump in its middle
+func (s *Service) moveClock(ctx context.Context, t time.Time) *responseFormatter.ResponseFormatter {
+	_, unlock := s.lock(ctx)
+	defer unlock()
+	c, ok := s.clock.(clock.Adjustable)
+	if !ok {
+		return responseFormatter.New(http.StatusConflict, "The clock of the service cannot be moved", true)
+	} else if t.Before(c.Now()) {
+		return responseFormatter.New(http.StatusBadRequest, "Time can only travel forward", true)
+	}
+	c.Set(t)
+	return nil
+}
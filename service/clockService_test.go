@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var monday = time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+
+func TestClock_TransactionsUseInjectedClock(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 10)
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, monday, trx.Time)
+	assert.Equal(t, monday, svc.Journal(ctx)[len(svc.Journal(ctx))-1].Time)
+}
+
+func TestClock_EachServiceHasItsClock(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	assert.Equal(t, monday, svc.FXRates(ctx)[0].UpdatedAt)
+	assert.Equal(t, monday, svc.Journal(ctx)[0].Time)
+
+	New(WithClock(clock.NewFake(monday.AddDate(1, 0, 0))))
+	acc, _ := svc.Withdraw(ctx, "112233", 10)
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, monday, trx.Time)
+	assert.Equal(t, monday, svc.Journal(ctx)[len(svc.Journal(ctx))-1].Time)
+}
+
+func TestClock_HoldsAndCardsExpire(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	expiresAt := monday.Add(time.Hour)
+	svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 50, Reason: "cheque clearing", ExpiresAt: &expiresAt})
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(50), acc.AvailableBalance)
+	c.Advance(time.Hour)
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+
+	c.Set(monday.AddDate(cardValidYears+1, 0, 0))
+	_, resp := svc.PINValidation(ctx, entity.CardLogin{CardNumber: johnCard, PIN: "012108"})
+	assert.Equal(t, ErrCodeCardExpired, resp.Code)
+}
+
+func TestTravelTo_RunsSkippedDays(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	svc.SetOverdraft(ctx, "112233", 1000, 36.5)
+	svc.Withdraw(ctx, "112233", 1000)
+	svc.RunJobs(ctx)
+
+	runs, resp := svc.TravelTo(ctx, monday.AddDate(0, 0, 3))
+	assert.Nil(t, resp)
+	assert.Len(t, runs, 9)
+	trxs, _ := svc.Transactions(ctx, "112233")
+	interest := 0
+	for _, trx := range trxs {
+		if trx.Type == entity.TransactionOverdraftInterest {
+			interest++
+		}
+	}
+	assert.Equal(t, 4, interest)
+
+	_, resp = svc.TravelTo(ctx, monday)
+	assert.Equal(t, "Time can only travel forward", resp.Message)
+	_, resp = New().TravelTo(ctx, monday.AddDate(1, 0, 0))
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
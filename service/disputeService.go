@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+var (
+	disputeList []*entity.Dispute
+	disputeMap  = make(map[string]*entity.Dispute)
+)
+
+func initDispute() {
+	disputeList = nil
+	disputeMap = make(map[string]*entity.Dispute)
+}
+
+// Dispute opens a dispute on one of the account's own transactions
+func (s *Service) Dispute(ctx context.Context, acctNbr, trxID, reason string) (*entity.Dispute, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	trx := trxMap[trxID]
+	if trx == nil || trx.AccountNumber != acctNbr {
+		return nil, responseFormatter.New(http.StatusNotFound, "Transaction not found", true)
+	} else if trx.Type != entity.TransactionWithdraw && trx.Type != entity.TransactionTransferOut {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Only withdrawals and outgoing transfers can be disputed", true)
+	} else if trx.ReversalStatus == entity.ReversalFull {
+		return nil, responseFormatter.New(http.StatusConflict, "Transaction is already reversed", true).
+			WithCode(ErrCodeAlreadyReversed)
+	} else if strings.Trim(reason, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Reason is required", true)
+	}
+	for _, d := range disputeList {
+		if d.TransactionID == trxID && d.Status == entity.DisputeOpen {
+			return nil, responseFormatter.New(http.StatusConflict, "Transaction already has an open dispute", true)
+		}
+	}
+	d := &entity.Dispute{
+		ID:            entity.FormatDisputeID(len(disputeList) + 1),
+		TransactionID: trxID,
+		AccountNumber: acctNbr,
+		Reason:        reason,
+		Status:        entity.DisputeOpen,
+		CreatedAt:     s.clock.Now(),
+	}
+	disputeList = append(disputeList, d)
+	disputeMap[d.ID] = d
+	s.save()
+	return d, nil
+}
+
+// Disputes lists the disputes with the given status, all of them when status is empty.
+// acctNbr, when set, only keeps the disputes of that account.
+func (s *Service) Disputes(ctx context.Context, acctNbr string, status entity.DisputeStatus) []*entity.Dispute {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	disputes := []*entity.Dispute{}
+	for _, d := range disputeList {
+		if (acctNbr == "" || d.AccountNumber == acctNbr) && (status == "" || d.Status == status) {
+			disputes = append(disputes, d)
+		}
+	}
+	return disputes
+}
+
+// ResolveDispute closes an open dispute. When reverse is set, amount of the transaction is reversed,
+// all of the amount not reversed yet when amount is 0, otherwise the dispute is rejected.
+func (s *Service) ResolveDispute(ctx context.Context, disputeID string, reverse bool, amount float64, resolution string) (*entity.Dispute, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	d := disputeMap[disputeID]
+	if d == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Dispute not found", true)
+	} else if d.Status != entity.DisputeOpen {
+		return nil, responseFormatter.New(http.StatusConflict, "Dispute is already resolved", true)
+	}
+	if reverse {
+		reason := "Dispute " + d.ID
+		if resolution != "" {
+			reason += " : " + resolution
+		}
+		reversal, resp := s.Reverse(ctx, d.TransactionID, amount, reason)
+		if resp != nil {
+			return nil, resp
+		}
+		d.Status = entity.DisputeReversed
+		d.ReversalID = reversal.ID
+	} else {
+		d.Status = entity.DisputeRejected
+	}
+	now := s.clock.Now()
+	d.Resolution = resolution
+	d.ResolvedAt = &now
+	s.save()
+	return d, nil
+}
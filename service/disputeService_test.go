@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispute_ResolvedByReversal(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 50)
+
+	_, resp := svc.Dispute(ctx, "112244", acc.TransactionID, "not mine")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	d, resp := svc.Dispute(ctx, "112233", acc.TransactionID, "no cash came out")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.DisputeOpen, d.Status)
+	_, resp = svc.Dispute(ctx, "112233", acc.TransactionID, "no cash came out")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Len(t, svc.Disputes(ctx, "", entity.DisputeOpen), 1)
+
+	d, resp = svc.ResolveDispute(ctx, d.ID, true, 0, "dispenser log shows a jam")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.DisputeReversed, d.Status)
+	assert.NotEmpty(t, d.ReversalID)
+	assert.NotNil(t, d.ResolvedAt)
+	balance, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), balance.Balance)
+
+	_, resp = svc.ResolveDispute(ctx, d.ID, false, 0, "")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	reloaded := New(WithRepository(repo))
+	disputes := reloaded.Disputes(ctx, "112233", "")
+	assert.Len(t, disputes, 1)
+	assert.Equal(t, entity.DisputeReversed, disputes[0].Status)
+}
+
+func TestDispute_Rejected(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 50)
+	d, _ := svc.Dispute(ctx, "112233", acc.TransactionID, "no cash came out")
+
+	d, resp := svc.ResolveDispute(ctx, d.ID, false, 0, "cash was dispensed")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.DisputeRejected, d.Status)
+	balance, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(50), balance.Balance)
+}
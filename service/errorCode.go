@@ -0,0 +1,24 @@
+package service
+
+// Error codes set on the ResponseFormatter for errors clients need to tell apart
+const (
+	ErrCodeAccountFrozen          = "ACCOUNT_FROZEN"
+	ErrCodeAccountClosed          = "ACCOUNT_CLOSED"
+	ErrCodeDestinationUnavailable = "DESTINATION_UNAVAILABLE"
+	ErrCodeAlreadyReversed        = "ALREADY_REVERSED"
+	ErrCodeDispenseFailed         = "DISPENSE_FAILED"
+	ErrCodeTransferNotAllowed     = "TRANSFER_NOT_ALLOWED"
+	ErrCodeCardBlocked            = "CARD_BLOCKED"
+	ErrCodeCardExpired            = "CARD_EXPIRED"
+	ErrCodeInsufficientBalance    = "INSUFFICIENT_BALANCE"
+	ErrCodeBillerUnavailable      = "BILLER_UNAVAILABLE"
+	ErrCodeInterbankDeclined      = "INTERBANK_DECLINED"
+	ErrCodeInterbankTimeout       = "INTERBANK_TIMEOUT"
+	ErrCodeCurrencyNotSupported   = "CURRENCY_NOT_SUPPORTED"
+	ErrCodeQuoteExpired           = "FX_QUOTE_EXPIRED"
+	ErrCodeCardlessExpired        = "CARDLESS_CODE_EXPIRED"
+	ErrCodeCardlessBlocked        = "CARDLESS_CODE_BLOCKED"
+	ErrCodeStepUpRequired         = "STEP_UP_REQUIRED"
+	ErrCodeInvalidOTP             = "INVALID_OTP"
+	ErrCodeOTPLocked              = "OTP_LOCKED"
+)
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// feeTransactionTypes are the transactions counted for the free operations of a month
+var feeTransactionTypes = map[fee.Operation]entity.TransactionType{
+	fee.Withdraw: entity.TransactionWithdraw,
+	fee.Transfer: entity.TransactionTransferOut,
+}
+
+// fee is the fee acc would be charged for an operation of amount on network, both in the currency of acc.
+// The fee rules are in the base currency.
+func (s *Service) fee(acc *entity.Account, network fee.Network, op fee.Operation, amount float64) float64 {
+	tier := acc.Tier
+	if tier == "" {
+		tier = entity.DefaultTier
+	}
+	charge := s.fees.Fee(op, s.toBase(acc, amount), fee.Usage{AccountTier: tier, Network: network, ThisMonth: s.monthlyCount(acc.AccountNumber, op)})
+	return s.fromBase(acc, charge)
+}
+
+// monthlyCount is the number of operations the account did in the current month
+func (s *Service) monthlyCount(acctNbr string, op fee.Operation) int {
+	now := s.clock.Now()
+	var count int
+	for _, trx := range trxList {
+		if trx.AccountNumber == acctNbr && trx.Type == feeTransactionTypes[op] &&
+			trx.Time.Year() == now.Year() && trx.Time.Month() == now.Month() {
+			count++
+		}
+	}
+	return count
+}
+
+// chargeFee posts the fee of trx as its own FEE transaction
+func (s *Service) chargeFee(ctx context.Context, trx *entity.Transaction, amount float64) *responseFormatter.ResponseFormatter {
+	if amount == 0 {
+		return nil
+	}
+	entry := ledger.FeeEntry(trx.AccountNumber, amount)
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return resp
+	}
+	s.recordTransaction(ctx, entity.Transaction{
+		ID:            entry.TransactionID,
+		ATMID:         trx.ATMID,
+		AccountNumber: trx.AccountNumber,
+		Type:          entity.TransactionFee,
+		Amount:        amount,
+		FeeOf:         trx.ID,
+	})
+	return nil
+}
+
+func insufficientBalance(acc *entity.Account, amount, fee float64) *responseFormatter.ResponseFormatter {
+	if fee == 0 {
+		return responseFormatter.New(http.StatusBadRequest, "Insufficient balance "+fx.FormatWhole(currencyOf(acc), amount), true).
+			WithCode(ErrCodeInsufficientBalance)
+	}
+	return responseFormatter.New(http.StatusBadRequest,
+		fmt.Sprintf("Insufficient balance %s plus a %s fee", fx.FormatWhole(currencyOf(acc), amount), money(acc, fee)), true).
+		WithCode(ErrCodeInsufficientBalance)
+}
+
+// FeeQuote is the fee the account would be charged for an operation on network, to show before the customer confirms it
+func (s *Service) FeeQuote(ctx context.Context, acctNbr string, network fee.Network, op fee.Operation, amount float64) (*entity.FeeQuote, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if accMap[acctNbr] == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	} else if _, ok := feeTransactionTypes[op]; !ok {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Operation should be WITHDRAW or TRANSFER", true)
+	} else if network != fee.OnUs && network != fee.Interbank {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Network should be ON_US or INTERBANK", true)
+	} else if amount <= 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid amount", true)
+	}
+	return &entity.FeeQuote{Operation: string(op), Network: string(network), Amount: amount,
+		Fee: s.fee(accMap[acctNbr], network, op, amount)}, nil
+}
+
+func (s *Service) FeeRules(ctx context.Context) []fee.Rule {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.fees.Rules()
+}
+
+func (s *Service) SetFeeRules(ctx context.Context, rules []fee.Rule) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	for _, r := range rules {
+		if _, ok := feeTransactionTypes[r.Operation]; !ok {
+			return responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Unknown fee operation %s", r.Operation), true)
+		}
+	}
+	if err := s.fees.SetRules(rules); err != nil {
+		return responseFormatter.New(http.StatusBadRequest, err.Error(), true)
+	}
+	return nil
+}
+
+// SetAccountTier moves an account to another tier, which changes the fees it is waived from
+func (s *Service) SetAccountTier(ctx context.Context, acctNbr, tier string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if tier == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Tier is required", true)
+	}
+	acc.Tier = tier
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServiceWithFees(t *testing.T, rules ...fee.Rule) *Service {
+	engine, err := fee.New(rules...)
+	assert.Nil(t, err)
+	return New(WithFees(engine))
+}
+
+func TestTransfer_ChargesFee(t *testing.T) {
+	svc := newServiceWithFees(t, fee.Rule{Operation: fee.Transfer, Flat: 1.5})
+	ctx := context.Background()
+	quote, resp := svc.FeeQuote(ctx, "112233", fee.OnUs, fee.Transfer, 50)
+	assert.Nil(t, resp)
+	assert.Equal(t, 1.5, quote.Fee)
+
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 50})
+	assert.Nil(t, resp)
+	assert.Equal(t, 1.5, acc.Fee)
+	assert.Equal(t, 48.5, acc.Balance)
+
+	trxs, _ := svc.Transactions(ctx, "112233")
+	assert.Len(t, trxs, 2)
+	assert.Equal(t, entity.TransactionFee, trxs[1].Type)
+	assert.Equal(t, acc.TransactionID, trxs[1].FeeOf)
+
+	receipt, _ := svc.IssueReceipt(ctx, acc.TransactionID)
+	assert.Equal(t, 1.5, receipt.Fee)
+	assert.Equal(t, 48.5, receipt.Balance)
+
+	report := svc.LedgerCheck(ctx)
+	assert.True(t, report.Balanced)
+	assert.Equal(t, -1.5, report.Balances["FEE_INCOME"])
+}
+
+func TestWithdraw_FeeInInsufficientBalanceCheck(t *testing.T) {
+	svc := newServiceWithFees(t, fee.Rule{Operation: fee.Withdraw, Flat: 2})
+	ctx := context.Background()
+	_, resp := svc.Withdraw(ctx, "112233", 100)
+	assert.Equal(t, "Insufficient balance $100 plus a $2.00 fee", resp.Message)
+
+	acc, resp := svc.Withdraw(ctx, "112233", 90)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(8), acc.Balance)
+}
+
+func TestWithdraw_FreeOperationsAndTierWaiver(t *testing.T) {
+	svc := newServiceWithFees(t, fee.Rule{Operation: fee.Withdraw, Flat: 1, FreePerMonth: 1, WaivedTiers: []string{"PREMIUM"}})
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 10)
+	assert.Equal(t, float64(0), acc.Fee)
+	acc, _ = svc.Withdraw(ctx, "112233", 10)
+	assert.Equal(t, float64(1), acc.Fee)
+
+	_, resp := svc.SetAccountTier(ctx, "112233", "PREMIUM")
+	assert.Nil(t, resp)
+	acc, _ = svc.Withdraw(ctx, "112233", 10)
+	assert.Equal(t, float64(0), acc.Fee)
+	assert.Equal(t, float64(69), acc.Balance)
+}
+
+func TestSetFeeRules_UnknownOperation(t *testing.T) {
+	svc := New()
+	resp := svc.SetFeeRules(context.Background(), []fee.Rule{{Operation: "DEPOSIT", Flat: 1}})
+	assert.NotNil(t, resp)
+	assert.Nil(t, svc.SetFeeRules(context.Background(), []fee.Rule{{Operation: fee.Withdraw, Flat: 1}}))
+	assert.Len(t, svc.FeeRules(context.Background()), 1)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// defaultFXQuoteValidity is how long the rate of a quote is locked when FX_QUOTE_VALIDITY is not set
+const defaultFXQuoteValidity = time.Minute
+
+// FX quotes only live for their validity window, so they are not saved
+var (
+	fxQuoteList []*entity.FXQuote
+	fxQuoteMap  = make(map[string]*entity.FXQuote)
+)
+
+func initFXQuote() {
+	fxQuoteList = nil
+	fxQuoteMap = make(map[string]*entity.FXQuote)
+}
+
+func fxQuoteValidity() time.Duration {
+	if value := envLib.GetEnv("FX_QUOTE_VALIDITY"); value != "" {
+		validity, err := time.ParseDuration(value)
+		if err == nil && validity > 0 {
+			return validity
+		}
+		log.Printf("Invalid FX_QUOTE_VALIDITY %q, using %s \n", value, defaultFXQuoteValidity)
+	}
+	return defaultFXQuoteValidity
+}
+
+// currencyOf is the currency of the balance of acc, accounts opened before currencies were added are in the base currency
+func currencyOf(acc *entity.Account) string {
+	if acc.Currency == "" {
+		return fx.Base
+	}
+	return acc.Currency
+}
+
+// fromBase converts amount of the base currency, like a limit or a fee of the rules, to the currency of acc.
+// Without a rate for the currency of acc the amount is kept as is.
+func (s *Service) fromBase(acc *entity.Account, amount float64) float64 {
+	conv, err := s.fx.Conversion(fx.Base, currencyOf(acc))
+	if err != nil {
+		return amount
+	}
+	return conv.Convert(amount)
+}
+
+// toBase converts amount of the currency of acc to the base currency, to be checked against the rules
+func (s *Service) toBase(acc *entity.Account, amount float64) float64 {
+	conv, err := s.fx.Conversion(currencyOf(acc), fx.Base)
+	if err != nil {
+		return amount
+	}
+	return conv.Convert(amount)
+}
+
+// money writes amount in the currency of acc, like $12.50
+func money(acc *entity.Account, amount float64) string {
+	return fx.Format(currencyOf(acc), amount)
+}
+
+// checkBaseCurrency rejects operations only the base currency can pay for, like ATM cash and bills
+func checkBaseCurrency(acc *entity.Account, operation string) *responseFormatter.ResponseFormatter {
+	if currency := currencyOf(acc); currency != fx.Base {
+		return responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("%s is only available from %s accounts, this account is in %s", operation, fx.Base, currency), true).
+			WithCode(ErrCodeCurrencyNotSupported)
+	}
+	return nil
+}
+
+func (s *Service) FXRates(ctx context.Context) []fx.Rate {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.fx.Rates()
+}
+
+// SetFXRate sets how much of currency one unit of the base currency buys from now on.
+// Rates are kept until the app restarts, quotes already given keep their rate.
+func (s *Service) SetFXRate(ctx context.Context, currency string, rate float64) (*fx.Rate, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	r := fx.Rate{Currency: strings.ToUpper(currency), Rate: rate, UpdatedAt: s.clock.Now()}
+	if err := s.fx.SetRate(r); err != nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, capitalize(err.Error()), true)
+	}
+	return &r, nil
+}
+
+// FXQuote locks the rate of a transfer of amount from acctNbr to toAcctNbr for the validity window,
+// to show the converted amount before the customer confirms the transfer
+func (s *Service) FXQuote(ctx context.Context, acctNbr, toAcctNbr string, amount float64) (*entity.FXQuote, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	from, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if accMap[toAcctNbr] == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	} else if resp := s.checkTransferAmount(from, amount); resp != nil {
+		return nil, resp
+	}
+	to := accMap[toAcctNbr]
+	conv, err := s.fx.Conversion(currencyOf(from), currencyOf(to))
+	if err != nil {
+		return nil, responseFormatter.New(http.StatusServiceUnavailable,
+			fmt.Sprintf("No exchange rate from %s to %s", currencyOf(from), currencyOf(to)), true)
+	}
+	now := s.clock.Now()
+	q := &entity.FXQuote{
+		ID:                entity.FormatFXQuoteID(len(fxQuoteList) + 1),
+		FromAccountNumber: acctNbr,
+		ToAccountNumber:   toAcctNbr,
+		FromCurrency:      conv.From,
+		ToCurrency:        conv.To,
+		Amount:            amount,
+		Rate:              conv.Rate,
+		ConvertedAmount:   conv.Convert(amount),
+		RateUpdatedAt:     conv.UpdatedAt,
+		ExpiresAt:         now.Add(fxQuoteValidity()),
+	}
+	fxQuoteList = append(fxQuoteList, q)
+	fxQuoteMap[q.ID] = q
+	return q, nil
+}
+
+// transferConversion is the rate a transfer from one account to the other is converted at : the locked rate
+// of its quote when it has one, the current rate otherwise. The quote is returned to be marked as used.
+func (s *Service) transferConversion(transfer entity.Transfer, from, to *entity.Account) (fx.Conversion, *entity.FXQuote, *responseFormatter.ResponseFormatter) {
+	if transfer.QuoteID == "" {
+		conv, err := s.fx.Conversion(currencyOf(from), currencyOf(to))
+		if err != nil {
+			return conv, nil, responseFormatter.New(http.StatusServiceUnavailable,
+				fmt.Sprintf("No exchange rate from %s to %s", currencyOf(from), currencyOf(to)), true)
+		}
+		return conv, nil, nil
+	}
+	q := fxQuoteMap[transfer.QuoteID]
+	switch {
+	case q == nil || q.FromAccountNumber != from.AccountNumber:
+		return fx.Conversion{}, nil, responseFormatter.New(http.StatusNotFound, "Quote not found", true)
+	case q.ToAccountNumber != to.AccountNumber || q.Amount != transfer.Amount:
+		return fx.Conversion{}, nil, responseFormatter.New(http.StatusBadRequest, "Quote does not match the transfer", true)
+	case q.UsedAt != nil:
+		return fx.Conversion{}, nil, responseFormatter.New(http.StatusConflict, "Quote was already used", true)
+	case !s.clock.Now().Before(q.ExpiresAt):
+		return fx.Conversion{}, nil, responseFormatter.New(http.StatusConflict, "Quote has expired, please request a new one", true).
+			WithCode(ErrCodeQuoteExpired)
+	}
+	return fx.Conversion{From: q.FromCurrency, To: q.ToCurrency, Rate: q.Rate, UpdatedAt: q.RateUpdatedAt}, q, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFXService(c clock.Clock) *Service {
+	provider, _ := fx.New(fx.Rate{Currency: "EUR", Rate: 0.9})
+	svc := New(WithClock(c), WithFX(provider))
+	svc.CreateAccount(context.Background(), entity.Account{
+		Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 1000, Currency: "eur",
+	})
+	return svc
+}
+
+func TestCreateAccount_Currency(t *testing.T) {
+	svc := newFXService(clock.NewFake(monday))
+	acc, _ := svc.GetAccount(context.Background(), "445566")
+	assert.Equal(t, "EUR", acc.Currency)
+	acc, _ = svc.GetAccount(context.Background(), "112233")
+	assert.Equal(t, fx.Base, acc.Currency)
+
+	_, resp := svc.CreateAccount(context.Background(), entity.Account{
+		Name: "Bob", AccountNumber: "445577", PIN: "445577", Currency: "JPY",
+	})
+	assert.Equal(t, "Currency should be USD or a currency with an exchange rate", resp.Message)
+}
+
+func TestTransfer_ConvertsCurrency(t *testing.T) {
+	svc := newFXService(clock.NewFake(monday))
+	ctx := context.Background()
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "445566", Amount: 50})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(50), acc.Balance)
+	assert.Equal(t, float64(45), acc.ConvertedAmount)
+	assert.Equal(t, "EUR", acc.ConvertedCurrency)
+	assert.Equal(t, 0.9, acc.FXRate)
+
+	to, _ := svc.GetAccount(ctx, "445566")
+	assert.Equal(t, float64(1045), to.Balance)
+	assert.Equal(t, float64(-50), gl.Balance(ledger.FXPositionAccount(fx.Base)))
+	assert.Equal(t, float64(45), gl.Balance(ledger.FXPositionAccount("EUR")))
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	receipt, _ := svc.IssueReceipt(ctx, acc.TransactionID)
+	assert.Equal(t, fx.Base, receipt.Currency)
+	assert.Equal(t, float64(45), receipt.ConvertedAmount)
+	assert.Equal(t, "EUR", receipt.ConvertedCurrency)
+}
+
+func TestTransfer_LimitsAndFeesInAccountCurrency(t *testing.T) {
+	provider, _ := fx.New(fx.Rate{Currency: "JPY", Rate: 150})
+	fees, _ := fee.New(fee.Rule{Operation: fee.Transfer, Flat: 2.5})
+	svc := New(WithClock(clock.NewFake(monday)), WithFX(provider), WithFees(fees))
+	ctx := context.Background()
+	svc.CreateAccount(ctx, entity.Account{
+		Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 300000, Currency: "JPY",
+	})
+
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 150001})
+	assert.Equal(t, "Maximum amount to transfer is JPY 150000", resp.Message)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 100})
+	assert.Equal(t, "Minimum amount to transfer is JPY 150", resp.Message)
+
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 15000})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(375), acc.Fee)
+	assert.Equal(t, float64(300000-15000-375), acc.Balance)
+	assert.Equal(t, fx.Base, acc.ConvertedCurrency)
+}
+
+func TestFXQuote_LocksRate(t *testing.T) {
+	svc := newFXService(clock.NewFake(monday))
+	ctx := context.Background()
+	quote, resp := svc.FXQuote(ctx, "445566", "112233", 90)
+	assert.Nil(t, resp)
+	assert.Equal(t, 1.111111, quote.Rate)
+	assert.Equal(t, float64(100), quote.ConvertedAmount)
+	assert.Equal(t, monday.Add(defaultFXQuoteValidity), quote.ExpiresAt)
+
+	_, resp = svc.SetFXRate(ctx, "eur", 0.8)
+	assert.Nil(t, resp)
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 90, QuoteID: quote.ID})
+	assert.Nil(t, resp)
+	assert.Equal(t, fx.Base, acc.ConvertedCurrency)
+	assert.Equal(t, float64(910), acc.Balance)
+
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 90, QuoteID: quote.ID})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "Quote was already used", resp.Message)
+
+	quote, _ = svc.FXQuote(ctx, "445566", "112233", 90)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 80, QuoteID: quote.ID})
+	assert.Equal(t, "Quote does not match the transfer", resp.Message)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "445566", Amount: 90, QuoteID: quote.ID})
+	assert.Equal(t, "Quote not found", resp.Message)
+}
+
+func TestFXQuote_Expires(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := newFXService(c)
+	ctx := context.Background()
+	quote, _ := svc.FXQuote(ctx, "445566", "112233", 90)
+	c.Advance(defaultFXQuoteValidity + time.Second)
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112233", Amount: 90, QuoteID: quote.ID})
+	assert.Equal(t, ErrCodeQuoteExpired, resp.Code)
+	acc, _ := svc.GetAccount(ctx, "445566")
+	assert.Equal(t, float64(1000), acc.Balance)
+}
+
+func TestFX_BaseCurrencyOnly(t *testing.T) {
+	svc := newFXService(clock.NewFake(monday))
+	_, resp := svc.Withdraw(context.Background(), "445566", 50)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, ErrCodeCurrencyNotSupported, resp.Code)
+	assert.Equal(t, "Cash withdrawal is only available from USD accounts, this account is in EUR", resp.Message)
+}
+
+func TestReverse_FXTransfer(t *testing.T) {
+	svc := newFXService(clock.NewFake(monday))
+	ctx := context.Background()
+	acc, _ := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "445566", Amount: 50})
+	_, resp := svc.SetFXRate(ctx, "EUR", 0.5)
+	assert.Nil(t, resp)
+	_, resp = svc.Reverse(ctx, acc.TransactionID, 0, "sent to the wrong account")
+	assert.Nil(t, resp)
+
+	from, _ := svc.GetAccount(ctx, "112233")
+	assert.Equal(t, float64(100), from.Balance)
+	to, _ := svc.GetAccount(ctx, "445566")
+	assert.Equal(t, float64(1000), to.Balance)
+	assert.Equal(t, float64(0), gl.Balance(ledger.FXPositionAccount("EUR")))
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+}
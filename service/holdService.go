@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// pendingWithdrawExpiry keeps a crash in the middle of a dispense from holding the money forever
+const pendingWithdrawExpiry = 5 * time.Minute
+
+var (
+	holdList []*entity.Hold
+	holdMap  = make(map[string]*entity.Hold)
+)
+
+func initHold() {
+	holdList = nil
+	holdMap = make(map[string]*entity.Hold)
+}
+
+// availableBalance is the balance of the account less its active holds
+func (s *Service) availableBalance(acc *entity.Account) float64 {
+	available := acc.Balance
+	now := s.clock.Now()
+	for _, h := range holdList {
+		if h.AccountNumber == acc.AccountNumber && h.Active(now) {
+			available -= h.Amount
+		}
+	}
+	return math.Round(available*100) / 100
+}
+
+func (s *Service) toAccountResponse(acc *entity.Account) *entity.AccountResponse {
+	resp := acc.ToAccountResponse()
+	resp.AvailableBalance = s.availableBalance(acc)
+	resp.Type = accountTypeOf(acc).Type
+	resp.Currency = currencyOf(acc)
+	resp.OverdraftHeadroom = s.overdraftHeadroom(acc)
+	resp.AccruedInterest = math.Round(acc.AccruedInterest*100) / 100
+	return resp
+}
+
+func (s *Service) placeHold(acctNbr string, holdType entity.HoldType, amount float64, reason string, expiresAt *time.Time) *entity.Hold {
+	h := &entity.Hold{
+		ID:            entity.FormatHoldID(len(holdList) + 1),
+		AccountNumber: acctNbr,
+		Type:          holdType,
+		Amount:        amount,
+		Reason:        reason,
+		CreatedAt:     s.clock.Now(),
+		ExpiresAt:     expiresAt,
+	}
+	holdList = append(holdList, h)
+	holdMap[h.ID] = h
+	return h
+}
+
+func (s *Service) releaseHold(h *entity.Hold) {
+	now := s.clock.Now()
+	h.ReleasedAt = &now
+}
+
+// PlaceHold reserves amount of an account's balance, hold is either an ADMIN or a DEPOSIT hold.
+// A hold without expiry is kept until it is released.
+func (s *Service) PlaceHold(ctx context.Context, acctNbr string, hold entity.Hold) (*entity.Hold, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	} else if hold.Type == "" {
+		hold.Type = entity.HoldAdmin
+	}
+	if hold.Type != entity.HoldAdmin && hold.Type != entity.HoldDeposit {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Hold type should be ADMIN or DEPOSIT", true)
+	} else if hold.Amount <= 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid hold amount", true)
+	} else if strings.Trim(hold.Reason, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Reason is required", true)
+	} else if hold.ExpiresAt != nil && !hold.ExpiresAt.After(s.clock.Now()) {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Hold expiry should be in the future", true)
+	}
+	h := s.placeHold(acctNbr, hold.Type, hold.Amount, hold.Reason, hold.ExpiresAt)
+	s.save()
+	return h, nil
+}
+
+func (s *Service) ReleaseHold(ctx context.Context, holdID string) (*entity.Hold, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	h := holdMap[holdID]
+	if h == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Hold not found", true)
+	} else if !h.Active(s.clock.Now()) {
+		return nil, responseFormatter.New(http.StatusConflict, "Hold is already released or expired", true)
+	}
+	s.releaseHold(h)
+	s.save()
+	return h, nil
+}
+
+// Holds lists the holds of an account, only the active ones unless all is set
+func (s *Service) Holds(ctx context.Context, acctNbr string, all bool) ([]*entity.Hold, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	}
+	holds := []*entity.Hold{}
+	now := s.clock.Now()
+	for _, h := range holdList {
+		if h.AccountNumber == acctNbr && (all || h.Active(now)) {
+			holds = append(holds, h)
+		}
+	}
+	return holds, nil
+}
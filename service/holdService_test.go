@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHold_ReducesAvailableBalance(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	h, resp := svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 70, Reason: "fraud check"})
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.HoldAdmin, h.Type)
+
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+	assert.Equal(t, float64(30), acc.AvailableBalance)
+
+	_, resp = svc.Withdraw(ctx, "112233", 40)
+	assert.Equal(t, "Insufficient balance $40", resp.Message)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 31})
+	assert.Equal(t, "Insufficient balance $31", resp.Message)
+	_, resp = svc.Withdraw(ctx, "112233", 30)
+	assert.Nil(t, resp)
+
+	_, resp = svc.ReleaseHold(ctx, h.ID)
+	assert.Nil(t, resp)
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(70), acc.AvailableBalance)
+	_, resp = svc.ReleaseHold(ctx, h.ID)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestHold_Expires(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	h, resp := svc.PlaceHold(ctx, "112233", entity.Hold{
+		Type:      entity.HoldDeposit,
+		Amount:    50,
+		Reason:    "cheque clearing",
+		ExpiresAt: &expiresAt,
+	})
+	assert.Nil(t, resp)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(50), acc.AvailableBalance)
+
+	expired := time.Now().Add(-time.Second)
+	h.ExpiresAt = &expired
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.AvailableBalance)
+	holds, _ := svc.Holds(ctx, "112233", false)
+	assert.Empty(t, holds)
+}
+
+func TestPlaceHold_Validation(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.PlaceHold(ctx, "112233", entity.Hold{Type: entity.HoldPendingWithdraw, Amount: 10, Reason: "x"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp = svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 0, Reason: "x"})
+	assert.Equal(t, "Invalid hold amount", resp.Message)
+	past := time.Now().Add(-time.Hour)
+	_, resp = svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 10, Reason: "x", ExpiresAt: &past})
+	assert.Equal(t, "Hold expiry should be in the future", resp.Message)
+	_, resp = svc.PlaceHold(ctx, "999999", entity.Hold{Amount: 10, Reason: "x"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHold_Saved(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	svc.PlaceHold(ctx, "112233", entity.Hold{Amount: 25, Reason: "court order"})
+
+	reloaded := New(WithRepository(repo))
+	acc, _ := reloaded.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(75), acc.AvailableBalance)
+}
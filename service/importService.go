@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/accountCsv"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// ImportAccounts creates the accounts of a Name,PIN,Balance,Account Number CSV file.
+// Lines that fail validation are rejected and reported, the other lines are imported unless dryRun is set.
+func (s *Service) ImportAccounts(ctx context.Context, r io.Reader, dryRun bool) (*entity.ImportReport, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	records, err := accountCsv.Read(r)
+	if err != nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, fmt.Sprintf("Failed reading csv : %s", err.Error()), true)
+	}
+	report := &entity.ImportReport{DryRun: dryRun, Total: len(records), Errors: []entity.ImportError{}}
+	seenRecords := make(map[string]int)
+	seenAccounts := make(map[string]int)
+	var accounts []entity.Account
+	for _, rec := range records {
+		reject := func(message string) {
+			report.Rejected++
+			report.Errors = append(report.Errors, entity.ImportError{
+				Line:          rec.Line,
+				AccountNumber: rec.Account.AccountNumber,
+				Message:       message,
+			})
+		}
+		if rec.Err != nil {
+			reject(rec.Err.Error())
+			continue
+		}
+		if line, ok := seenRecords[rec.Raw]; ok {
+			reject(fmt.Sprintf("Duplicate record of line %d", line))
+			continue
+		}
+		seenRecords[rec.Raw] = rec.Line
+		if line, ok := seenAccounts[rec.Account.AccountNumber]; ok {
+			reject(fmt.Sprintf("Duplicate account number, already used on line %d", line))
+			continue
+		}
+		if resp := s.validateNewAccount(rec.Account); resp != nil {
+			reject(resp.Message)
+			continue
+		}
+		seenAccounts[rec.Account.AccountNumber] = rec.Line
+		accounts = append(accounts, rec.Account)
+	}
+	report.Imported = len(accounts)
+	if dryRun {
+		return report, nil
+	}
+	for _, acc := range accounts {
+		acc := acc
+		acc.Status = entity.AccountActive
+		if resp := s.openAccount(&acc); resp != nil {
+			// keep the accounts opened before the failing one
+			s.save()
+			return nil, resp
+		}
+	}
+	s.save()
+	return report, nil
+}
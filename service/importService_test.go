@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const importCsv = `Name,PIN,Balance,Account Number
+Richard Roe,123456,150,200001
+Mary Major,12a456,50,200002
+Richard Roe,123456,150,200001
+Other Person,111111,10,200001
+Existing Person,111111,10,112233
+`
+
+func TestImportAccounts_Report(t *testing.T) {
+	svc := New()
+	report, resp := svc.ImportAccounts(context.Background(), strings.NewReader(importCsv), false)
+	assert.Nil(t, resp)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, 4, report.Rejected)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Equal(t, "PIN should only contains numbers", report.Errors[0].Message)
+	assert.Equal(t, "Duplicate record of line 2", report.Errors[1].Message)
+	assert.Equal(t, "Duplicate account number, already used on line 2", report.Errors[2].Message)
+	assert.Equal(t, "Account Number already exists", report.Errors[3].Message)
+
+	acc, resp := svc.GetAccount(context.Background(), "200001")
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(150), acc.Balance)
+}
+
+func TestImportAccounts_DryRun(t *testing.T) {
+	svc := New()
+	report, resp := svc.ImportAccounts(context.Background(), strings.NewReader(importCsv), true)
+	assert.Nil(t, resp)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Imported)
+
+	_, resp = svc.GetAccount(context.Background(), "200001")
+	assert.NotNil(t, resp)
+}
+
+func TestImportAccounts_NonFiniteBalance(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := New(WithRepository(repo))
+	csv := "Name,PIN,Balance,Account Number\nNot A Number,123456,NaN,300001\nInfinite,123456,Inf,300002\nRichard Roe,123456,150,300003\n"
+	report, resp := svc.ImportAccounts(context.Background(), strings.NewReader(csv), false)
+	assert.Nil(t, resp)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, 2, report.Rejected)
+	assert.Equal(t, `Invalid balance "NaN"`, report.Errors[0].Message)
+
+	_, resp = svc.GetAccount(context.Background(), "300001")
+	assert.NotNil(t, resp)
+	snapshot, _ := repo.Load()
+	assert.Equal(t, "300003", snapshot.Accounts[len(snapshot.Accounts)-1].AccountNumber)
+	assert.True(t, svc.LedgerCheck(context.Background()).Balanced)
+
+	_, resp = svc.CreateAccount(context.Background(), entity.Account{Name: "Infinite", PIN: "123456", AccountNumber: "300004", Balance: math.Inf(1)})
+	assert.Equal(t, "Invalid initial balance", resp.Message)
+}
+
+func TestOpenAccount_NotAddedWhenOpeningBalanceFails(t *testing.T) {
+	svc := New()
+	resp := svc.openAccount(&entity.Account{Name: "Not A Number", PIN: "123456", AccountNumber: "300001", Balance: math.NaN()})
+	assert.NotNil(t, resp)
+	assert.Nil(t, accMap["300001"])
+	assert.Empty(t, cardsOf("300001"))
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/interbank"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+const (
+	// defaultBankName is the name of this bank when BANK_NAME is not set
+	defaultBankName = "ATM Simulation Bank"
+	// defaultInterbankTimeout is how long the switch waits for another bank when INTERBANK_TIMEOUT is not set
+	defaultInterbankTimeout = 5 * time.Second
+	// pendingInterbankExpiry keeps a crash while the switch sends a transfer from holding the money forever
+	pendingInterbankExpiry = 5 * time.Minute
+)
+
+// BankName is the name of the bank the accounts of this app belong to
+func BankName() string {
+	if name := envLib.GetEnv("BANK_NAME"); name != "" {
+		return name
+	}
+	return defaultBankName
+}
+
+// defaultNetwork is a switch to the simulated banks, with the timeout of INTERBANK_TIMEOUT
+func defaultNetwork() *interbank.Switch {
+	timeout := defaultInterbankTimeout
+	if value := envLib.GetEnv("INTERBANK_TIMEOUT"); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			log.Fatalf("Invalid INTERBANK_TIMEOUT %q, use a duration like 5s \n", value)
+		}
+	}
+	network := interbank.NewSwitch(timeout)
+	for _, b := range interbank.DefaultBanks() {
+		network.Register(b)
+	}
+	return network
+}
+
+// Banks lists the banks customers can transfer to, this bank included
+func (s *Service) Banks(ctx context.Context) []interbank.BankInfo {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.network.Banks()
+}
+
+// SetBankFault makes a simulated bank of the network decline or not answer, to test how transfers to it fail
+func (s *Service) SetBankFault(ctx context.Context, bankCode string, fault interbank.Fault) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	b, ok := s.network.Bank(bankCode)
+	if !ok {
+		return responseFormatter.New(http.StatusNotFound, "Bank not found", true)
+	}
+	simulated, ok := b.(*interbank.SimulatedBank)
+	if !ok {
+		return responseFormatter.New(http.StatusBadRequest, "Only simulated banks can be given a fault", true)
+	}
+	simulated.SetFault(fault)
+	return nil
+}
+
+// TransferInquiry looks up the holder of the destination of a transfer, so the customer can check
+// the masked name before confirming. An empty bankCode is this bank.
+func (s *Service) TransferInquiry(ctx context.Context, bankCode, acctNbr string) (*entity.TransferInquiry, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if bankCode == "" {
+		bankCode = BankCode()
+	}
+	if strings.TrimSpace(acctNbr) == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	}
+	name, err := s.network.Inquire(ctx, bankCode, acctNbr)
+	if err != nil {
+		return nil, interbankError(err)
+	}
+	b, _ := s.network.Bank(bankCode)
+	return &entity.TransferInquiry{
+		BankCode:      bankCode,
+		BankName:      b.Name(),
+		AccountNumber: acctNbr,
+		MaskedName:    maskName(name),
+	}, nil
+}
+
+// interbankTransfer sends a transfer to an account of another bank through the switch. The destination bank
+// is asked first whether the account can receive it, then the money and the fee are held while the switch
+// sends it, the account is only charged when the other bank credits it.
+func (s *Service) interbankTransfer(ctx context.Context, transfer entity.Transfer) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	from := accMap[transfer.FromAccountNumber]
+	if transfer.FromAccountNumber == "" || transfer.ToAccountNumber == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	} else if from == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	} else if _, ok := s.network.Bank(transfer.BankCode); !ok {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Unknown bank code", true)
+	} else if resp := checkAccountStatus(from); resp != nil {
+		return nil, resp
+	} else if resp := checkBaseCurrency(from, "Transfer to another bank"); resp != nil {
+		return nil, resp
+	} else if resp := s.checkTransferAmount(from, transfer.Amount); resp != nil {
+		return nil, resp
+	} else if resp := checkTransferOut(from); resp != nil {
+		return nil, resp
+	} else if strings.Trim(transfer.ReferenceNumber, " ") != "" {
+		if _, err := strconv.Atoi(transfer.ReferenceNumber); err != nil {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Reference Number", true)
+		}
+	}
+	charge := s.fee(from, fee.Interbank, fee.Transfer, transfer.Amount)
+	if s.spendableBalance(from) < transfer.Amount+charge {
+		return nil, insufficientBalance(from, transfer.Amount, charge)
+	} else if resp := s.checkMinimumBalance(from, transfer.Amount+charge); resp != nil {
+		return nil, resp
+	} else if _, err := s.network.Inquire(ctx, transfer.BankCode, transfer.ToAccountNumber); err != nil {
+		return nil, interbankError(err)
+	} else if resp := s.checkStepUp(ctx, from, s.transferStepUp(from, transfer.BankCode, transfer.ToAccountNumber, transfer.Amount)); resp != nil {
+		return nil, resp
+	}
+
+	expiresAt := s.clock.Now().Add(pendingInterbankExpiry)
+	hold := s.placeHold(from.AccountNumber, entity.HoldPendingInterbank, transfer.Amount+charge,
+		fmt.Sprintf("Transfer to %s at bank %s", transfer.ToAccountNumber, transfer.BankCode), &expiresAt)
+	err := s.network.Transfer(ctx, interbank.Request{
+		ID:          hold.ID,
+		FromBank:    BankCode(),
+		FromAccount: from.AccountNumber,
+		FromName:    from.Name,
+		ToBank:      transfer.BankCode,
+		ToAccount:   transfer.ToAccountNumber,
+		Amount:      transfer.Amount,
+		Reference:   transfer.ReferenceNumber,
+	})
+	s.releaseHold(hold)
+	if err != nil {
+		s.save()
+		log.Printf("Interbank transfer %s of $%.2f to bank %s failed : %s \n", hold.ID, transfer.Amount, transfer.BankCode, err.Error())
+		return nil, interbankError(err)
+	}
+	entry := ledger.InterbankOutEntry(from.AccountNumber, transfer.BankCode, transfer.Amount)
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return nil, resp
+	}
+	trx := s.recordTransaction(ctx, entity.Transaction{
+		ID:                       entry.TransactionID,
+		AccountNumber:            from.AccountNumber,
+		Type:                     entity.TransactionTransferOut,
+		Amount:                   transfer.Amount,
+		CounterpartAccountNumber: transfer.ToAccountNumber,
+		CounterpartBankCode:      transfer.BankCode,
+		ReferenceNumber:          transfer.ReferenceNumber,
+	})
+	if resp := s.chargeFee(ctx, trx, charge); resp != nil {
+		return nil, resp
+	}
+	s.save()
+	accResp := s.toAccountResponse(from)
+	accResp.TransactionID = trx.ID
+	accResp.Fee = charge
+	return accResp, nil
+}
+
+func interbankError(err error) *responseFormatter.ResponseFormatter {
+	switch {
+	case errors.Is(err, interbank.ErrUnknownBank):
+		return responseFormatter.New(http.StatusBadRequest, "Unknown bank code", true)
+	case errors.Is(err, interbank.ErrAccountNotFound):
+		return responseFormatter.New(http.StatusNotFound, "Destination account not found", true)
+	case errors.Is(err, interbank.ErrDeclined):
+		return responseFormatter.New(http.StatusBadRequest,
+			"Destination bank declined the transfer, your account has not been charged", true).
+			WithCode(ErrCodeInterbankDeclined)
+	case errors.Is(err, interbank.ErrTimeout):
+		return responseFormatter.New(http.StatusGatewayTimeout,
+			"Destination bank did not answer in time, your account has not been charged", true).
+			WithCode(ErrCodeInterbankTimeout)
+	}
+	return responseFormatter.New(http.StatusBadGateway, "Interbank network is not available, please try again later", true)
+}
+
+// localBank is this bank as a member of the interbank network, it pays the transfers other banks send to its accounts
+type localBank struct {
+	s *Service
+}
+
+func (b *localBank) Code() string {
+	return BankCode()
+}
+
+func (b *localBank) Name() string {
+	return BankName()
+}
+
+func (b *localBank) Inquire(ctx context.Context, acctNbr string) (string, error) {
+	_, unlock := b.s.lock(ctx)
+	defer unlock()
+	acc := accMap[acctNbr]
+	if acc == nil || acc.Status == entity.AccountClosed {
+		return "", interbank.ErrAccountNotFound
+	} else if !canReceiveInterbank(acc) {
+		return "", interbank.ErrDeclined
+	}
+	return acc.Name, nil
+}
+
+// canReceiveInterbank tells whether acc accepts transfers from other banks, they are always in the base currency
+func canReceiveInterbank(acc *entity.Account) bool {
+	return acc.Status != entity.AccountFrozen && accountTypeOf(acc).CanReceiveTransfer && currencyOf(acc) == fx.Base
+}
+
+func (b *localBank) Credit(ctx context.Context, req interbank.Request) error {
+	ctx, unlock := b.s.lock(ctx)
+	defer unlock()
+	acc := accMap[req.ToAccount]
+	if acc == nil || acc.Status == entity.AccountClosed {
+		return interbank.ErrAccountNotFound
+	} else if !canReceiveInterbank(acc) || req.Amount <= 0 {
+		return interbank.ErrDeclined
+	}
+	entry := ledger.InterbankInEntry(req.FromBank, acc.AccountNumber, req.Amount)
+	entry.TransactionID = nextTransactionID()
+	if _, resp := b.s.post(entry); resp != nil {
+		log.Printf("Interbank transfer %s from bank %s failed : %s \n", req.ID, req.FromBank, resp.Message)
+		return interbank.ErrDeclined
+	}
+	b.s.recordTransaction(WithATM(ctx, systemATMID), entity.Transaction{
+		ID:                       entry.TransactionID,
+		AccountNumber:            acc.AccountNumber,
+		Type:                     entity.TransactionTransferIn,
+		Amount:                   req.Amount,
+		CounterpartAccountNumber: req.FromAccount,
+		CounterpartBankCode:      req.FromBank,
+		ReferenceNumber:          req.Reference,
+	})
+	b.s.save()
+	return nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/interbank"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInterbankService() (*Service, *interbank.Switch, *interbank.SimulatedBank) {
+	network := interbank.NewSwitch(50 * time.Millisecond)
+	bank := interbank.NewSimulatedBank("002", "Second Bank", interbank.Account{Number: "210001", Name: "Alice Smith", Balance: 500})
+	network.Register(bank)
+	return New(WithNetwork(network)), network, bank
+}
+
+func TestTransfer_InterbankFee(t *testing.T) {
+	network := interbank.NewSwitch(50 * time.Millisecond)
+	network.Register(interbank.NewSimulatedBank("002", "Second Bank", interbank.Account{Number: "210001", Name: "Alice Smith", Balance: 500}))
+	engine, _ := fee.New(fee.Rule{Operation: fee.Transfer, Flat: 1}, fee.Rule{Operation: fee.Transfer, Network: fee.Interbank, Flat: 3})
+	svc := New(WithNetwork(network), WithFees(engine))
+	ctx := context.Background()
+
+	quote, resp := svc.FeeQuote(ctx, "112233", fee.Interbank, fee.Transfer, 30)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(3), quote.Fee)
+
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 30})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(1), acc.Fee)
+	acc, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "210001", BankCode: "002", Amount: 30})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(3), acc.Fee)
+	assert.Equal(t, float64(36), acc.Balance)
+}
+
+// heldBank records the available balance of the sender while the transfer is being credited
+type heldBank struct {
+	*interbank.SimulatedBank
+	svc       *Service
+	available float64
+}
+
+func (b *heldBank) Credit(ctx context.Context, req interbank.Request) error {
+	b.available = b.svc.availableBalance(accMap[req.FromAccount])
+	return b.SimulatedBank.Credit(ctx, req)
+}
+
+func TestTransfer_InterbankHoldsAmountAndFee(t *testing.T) {
+	network := interbank.NewSwitch(50 * time.Millisecond)
+	engine, _ := fee.New(fee.Rule{Operation: fee.Transfer, Network: fee.Interbank, Flat: 3})
+	svc := New(WithNetwork(network), WithFees(engine))
+	bank := &heldBank{SimulatedBank: interbank.NewSimulatedBank("002", "Second Bank", interbank.Account{Number: "210001", Name: "Alice Smith"}), svc: svc}
+	network.Register(bank)
+
+	acc, resp := svc.Transfer(context.Background(), entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "210001", BankCode: "002", Amount: 30})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(67), bank.available)
+	assert.Equal(t, float64(67), acc.AvailableBalance)
+}
+
+func TestTransfer_InterbankDestinationCannotReceive(t *testing.T) {
+	network := interbank.NewSwitch(50 * time.Millisecond)
+	bank := interbank.NewSimulatedBank("002", "Second Bank", interbank.Account{Number: "210009", Name: "Dan Gray", Blocked: true})
+	network.Register(bank)
+	svc := New(WithNetwork(network))
+
+	_, resp := svc.Transfer(context.Background(), entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "210009", BankCode: "002", Amount: 30})
+	assert.Equal(t, ErrCodeInterbankDeclined, resp.Code)
+	assert.Empty(t, holdList)
+	assert.Empty(t, bank.Received())
+}
+
+func TestTransferInquiry(t *testing.T) {
+	svc, _, _ := newInterbankService()
+	ctx := context.Background()
+	inquiry, resp := svc.TransferInquiry(ctx, "002", "210001")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.TransferInquiry{BankCode: "002", BankName: "Second Bank", AccountNumber: "210001", MaskedName: "A**** S****"}, *inquiry)
+
+	inquiry, resp = svc.TransferInquiry(ctx, "", "112244")
+	assert.Nil(t, resp)
+	assert.Equal(t, BankCode(), inquiry.BankCode)
+	assert.Equal(t, BankName(), inquiry.BankName)
+
+	_, resp = svc.TransferInquiry(ctx, "002", "999999")
+	assert.Equal(t, "Destination account not found", resp.Message)
+	_, resp = svc.TransferInquiry(ctx, "009", "210001")
+	assert.Equal(t, "Unknown bank code", resp.Message)
+}
+
+func TestTransfer_ToOtherBank(t *testing.T) {
+	svc, _, bank := newInterbankService()
+	ctx := context.Background()
+	acc, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "210001", BankCode: "002", Amount: 30})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(70), acc.Balance)
+
+	remote, _ := bank.Account("210001")
+	assert.Equal(t, float64(530), remote.Balance)
+	assert.Equal(t, "112233", bank.Received()[0].FromAccount)
+	assert.Equal(t, float64(-30), gl.Balance(ledger.SettlementAccount("002")))
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, entity.TransactionTransferOut, trx.Type)
+	assert.Equal(t, "002", trx.CounterpartBankCode)
+	receipt, _ := svc.IssueReceipt(ctx, trx.ID)
+	assert.Equal(t, "Second Bank", receipt.DestinationBank)
+
+	_, resp = svc.Reverse(ctx, trx.ID, 0, "")
+	assert.Equal(t, "Transfers to other banks cannot be reversed", resp.Message)
+}
+
+func TestTransfer_ToOtherBankFails(t *testing.T) {
+	svc, _, bank := newInterbankService()
+	ctx := context.Background()
+	tests := []struct {
+		fault   interbank.Fault
+		to      string
+		status  int
+		code    string
+		message string
+	}{
+		{interbank.FaultNone, "999999", http.StatusNotFound, "", "Destination account not found"},
+		{interbank.FaultDecline, "210001", http.StatusBadRequest, ErrCodeInterbankDeclined,
+			"Destination bank declined the transfer, your account has not been charged"},
+		{interbank.FaultTimeout, "210001", http.StatusGatewayTimeout, ErrCodeInterbankTimeout,
+			"Destination bank did not answer in time, your account has not been charged"},
+	}
+	for _, tt := range tests {
+		bank.SetFault(tt.fault)
+		_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: tt.to, BankCode: "002", Amount: 30})
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.code, resp.Code)
+			assert.Equal(t, tt.message, resp.Message)
+		}
+	}
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), acc.Balance)
+	assert.Equal(t, float64(100), svc.availableBalance(accMap["112233"]))
+	assert.Empty(t, bank.Received())
+	assert.Equal(t, float64(0), gl.Balance(ledger.SettlementAccount("002")))
+}
+
+func TestTransfer_FromOtherBank(t *testing.T) {
+	svc, network, _ := newInterbankService()
+	ctx := context.Background()
+	before := accMap["112244"].Balance
+	err := network.Transfer(ctx, interbank.Request{ID: "R1", FromBank: "002", FromAccount: "210001", ToBank: BankCode(), ToAccount: "112244", Amount: 25})
+	assert.Nil(t, err)
+	acc, _ := svc.BalanceCheck(ctx, "112244")
+	assert.Equal(t, before+25, acc.Balance)
+	assert.Equal(t, float64(25), gl.Balance(ledger.SettlementAccount("002")))
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	trxs, _ := svc.Transactions(ctx, "112244")
+	last := trxs[len(trxs)-1]
+	assert.Equal(t, entity.TransactionTransferIn, last.Type)
+	assert.Equal(t, "002", last.CounterpartBankCode)
+
+	svc.FreezeAccount(ctx, "112244")
+	err = network.Transfer(ctx, interbank.Request{ID: "R2", FromBank: "002", FromAccount: "210001", ToBank: BankCode(), ToAccount: "112244", Amount: 25})
+	assert.Equal(t, interbank.ErrDeclined, err)
+}
+
+func TestAddBeneficiary_OtherBank(t *testing.T) {
+	svc, _, bank := newInterbankService()
+	ctx := context.Background()
+	b, resp := svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: "Alice", ToAccountNumber: "210001", BankCode: "002"})
+	assert.Nil(t, resp)
+	assert.Equal(t, "A**** S****", b.MaskedName)
+	_, resp = svc.AddBeneficiary(ctx, "112233", entity.Beneficiary{Nickname: "Nobody", ToAccountNumber: "999999", BankCode: "002"})
+	assert.Equal(t, "Beneficiary account not found", resp.Message)
+
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", BeneficiaryID: b.ID, Amount: 10})
+	assert.Nil(t, resp)
+	assert.Equal(t, "210001", bank.Received()[0].ToAccount)
+}
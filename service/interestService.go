@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/interest"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+)
+
+// AccrueInterest adds the interest of day to the accounts whose type earns interest, and posts
+// the accrued interest as an INTEREST transaction on posting days. An account accrues at most once a day.
+func (s *Service) AccrueInterest(ctx context.Context, day time.Time) []*entity.Transaction {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	date := day.Format(scheduler.DateFormat)
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+	posted := []*entity.Transaction{}
+	for _, acc := range sortedAccounts() {
+		rule, ok := s.interest.Rule(string(accountTypeOf(acc).Type))
+		if !ok || acc.Status == entity.AccountClosed || acc.InterestAccruedOn >= date {
+			continue
+		}
+		balance := gl.CustomerBalanceAt(acc.AccountNumber, endOfDay)
+		acc.AccruedInterest += rule.Accrue(balance, acc.AccruedInterest)
+		acc.InterestAccruedOn = date
+		amount := math.Round(acc.AccruedInterest*100) / 100
+		if !rule.PostingDay(day) || amount < 0.01 {
+			continue
+		}
+		entry := ledger.InterestEntry(acc.AccountNumber, amount)
+		entry.TransactionID = nextTransactionID()
+		if _, resp := s.post(entry); resp != nil {
+			log.Printf("Interest of %s : %s \n", acc.AccountNumber, resp.Message)
+			continue
+		}
+		acc.AccruedInterest -= amount
+		posted = append(posted, s.recordTransaction(ctx, entity.Transaction{
+			ID:            entry.TransactionID,
+			ATMID:         systemATMID,
+			AccountNumber: acc.AccountNumber,
+			Type:          entity.TransactionInterest,
+			Amount:        amount,
+		}))
+	}
+	s.save()
+	return posted
+}
+
+func (s *Service) InterestRules(ctx context.Context) []interest.Rule {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.interest.Rules()
+}
+
+// SetInterestRules replaces all the interest rules, they are kept until the app restarts
+func (s *Service) SetInterestRules(ctx context.Context, rules []interest.Rule) *responseFormatter.ResponseFormatter {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	for _, r := range rules {
+		if _, ok := accountTypes[entity.AccountType(r.AccountType)]; !ok {
+			return responseFormatter.New(http.StatusBadRequest, "Account type should be SAVINGS, CHECKING or CHECKING_OVERDRAFT", true)
+		}
+	}
+	if err := s.interest.SetRules(rules); err != nil {
+		return responseFormatter.New(http.StatusBadRequest, err.Error(), true)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/interest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInterestService(t *testing.T, rules ...interest.Rule) *Service {
+	rates, err := interest.New(rules...)
+	assert.Nil(t, err)
+	// the account is opened before the days the tests accrue, they accrue on its balance at the end of each day
+	svc := New(WithInterest(rates), WithClock(clock.NewFake(time.Date(2026, 9, 30, 9, 0, 0, 0, time.Local))))
+	_, resp := svc.CreateAccount(context.Background(), entity.Account{
+		Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 1000, Type: entity.AccountSavings,
+	})
+	assert.Nil(t, resp)
+	return svc
+}
+
+func TestAccrueInterest_PostedMonthly(t *testing.T) {
+	svc := newInterestService(t, interest.Rule{AccountType: "SAVINGS", Rate: 36.5, Compounding: interest.Monthly})
+	ctx := context.Background()
+	for day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local); day.Month() == time.October; day = day.AddDate(0, 0, 1) {
+		posted := svc.AccrueInterest(ctx, day)
+		if day.Day() < 31 {
+			assert.Empty(t, posted)
+		} else {
+			assert.Len(t, posted, 1)
+			assert.Equal(t, entity.TransactionInterest, posted[0].Type)
+			assert.Equal(t, float64(31), posted[0].Amount)
+			assert.Equal(t, float64(1031), posted[0].Balance)
+		}
+	}
+	acc, _ := svc.BalanceCheck(ctx, "445566")
+	assert.Zero(t, acc.AccruedInterest)
+	checking, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), checking.Balance)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+}
+
+func TestAccrueInterest_DailyCompounding(t *testing.T) {
+	svc := newInterestService(t, interest.Rule{AccountType: "SAVINGS", Rate: 36.5})
+	ctx := context.Background()
+	svc.AccrueInterest(ctx, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local))
+	svc.AccrueInterest(ctx, time.Date(2026, 10, 2, 0, 0, 0, 0, time.Local))
+	acc, _ := svc.BalanceCheck(ctx, "445566")
+	assert.Equal(t, float64(1000), acc.Balance)
+	assert.Equal(t, 2.0, acc.AccruedInterest)
+	assert.InDelta(t, 2.001, accMap["445566"].AccruedInterest, 1e-9)
+}
+
+func TestAccrueInterest_CatchUpAccruesBalanceOfEachDay(t *testing.T) {
+	rates, _ := interest.New(interest.Rule{AccountType: "SAVINGS", Rate: 36.5})
+	c := clock.NewFake(monday)
+	svc := New(WithInterest(rates), WithClock(c))
+	ctx := context.Background()
+	svc.CreateAccount(ctx, entity.Account{Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 1000, Type: entity.AccountSavings})
+	c.Advance(2 * 24 * time.Hour)
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "445566", Amount: 100})
+	assert.Nil(t, resp)
+
+	svc.AccrueInterest(ctx, monday)
+	svc.AccrueInterest(ctx, monday.AddDate(0, 0, 1))
+	svc.AccrueInterest(ctx, monday.AddDate(0, 0, 2))
+	assert.InDelta(t, 1+1.001+1.102001, accMap["445566"].AccruedInterest, 1e-9)
+}
+
+func TestAccrueInterest_OncePerDay(t *testing.T) {
+	svc := newInterestService(t, interest.Rule{AccountType: "SAVINGS", Rate: 36.5, Posting: interest.Daily})
+	ctx := context.Background()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	assert.Len(t, svc.AccrueInterest(ctx, day), 1)
+	assert.Empty(t, svc.AccrueInterest(ctx, day))
+	acc, _ := svc.BalanceCheck(ctx, "445566")
+	assert.Equal(t, float64(1001), acc.Balance)
+}
+
+func TestSetInterestRules(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	resp := svc.SetInterestRules(ctx, []interest.Rule{{AccountType: "BROKERAGE", Rate: 1}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = svc.SetInterestRules(ctx, []interest.Rule{{AccountType: "SAVINGS", Rate: 1, Posting: "WEEKLY"}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Nil(t, svc.SetInterestRules(ctx, []interest.Rule{{AccountType: "SAVINGS", Rate: 1}}))
+	assert.Len(t, svc.InterestRules(ctx), 1)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+)
+
+// Names of the daily batch jobs
+const (
+	JobOverdraftInterest = "overdraft-interest"
+	JobInterest          = "interest"
+	JobStandingOrders    = "standing-orders"
+)
+
+// initJobs adds the batch jobs, every job takes the lock of the service for the run of one day.
+// The scheduler runs one day at a time, so the lock is never held while waiting for the scheduler.
+func (s *Service) initJobs() {
+	s.jobs = scheduler.New(s.clock)
+	s.jobs.Add(scheduler.Job{Name: JobOverdraftInterest, Run: func(ctx context.Context, day time.Time) error {
+		ctx, unlock := s.lock(ctx)
+		defer unlock()
+		s.AccrueOverdraftInterest(ctx, day)
+		return nil
+	}})
+	s.jobs.Add(scheduler.Job{Name: JobInterest, Run: func(ctx context.Context, day time.Time) error {
+		ctx, unlock := s.lock(ctx)
+		defer unlock()
+		s.AccrueInterest(ctx, day)
+		return nil
+	}})
+	s.jobs.Add(scheduler.Job{Name: JobStandingOrders, Run: func(ctx context.Context, day time.Time) error {
+		ctx, unlock := s.lock(ctx)
+		defer unlock()
+		s.RunStandingOrders(ctx, day)
+		return nil
+	}})
+}
+
+// RunJobs runs the batch jobs for the days they have not run for yet.
+// It takes the lock for every run, it cannot be called while holding it.
+func (s *Service) RunJobs(ctx context.Context) []scheduler.Run {
+	runs := s.jobs.RunDue(ctx)
+	_, unlock := s.lock(ctx)
+	defer unlock()
+	s.save()
+	return runs
+}
+
+func (s *Service) Jobs(ctx context.Context) []scheduler.Status {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return s.jobs.Status()
+}
+
+// StartJobs checks every interval for batch jobs to run until ctx is done
+func (s *Service) StartJobs(ctx context.Context, interval time.Duration) {
+	s.jobs.Start(ctx, interval, func(runs []scheduler.Run) {
+		for _, r := range runs {
+			if r.Error != "" {
+				log.Printf("Job %s of %s failed : %s \n", r.Job, r.Day, r.Error)
+			}
+		}
+		if len(runs) > 0 {
+			_, unlock := s.lock(ctx)
+			s.save()
+			unlock()
+		}
+	})
+}
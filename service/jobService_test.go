@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunJobs_IdempotentAcrossRestarts(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	svc.SetOverdraft(ctx, "112233", 500, 36.5)
+	svc.Withdraw(ctx, "112233", 300)
+
+	today := time.Now().Format(scheduler.DateFormat)
+	runs := svc.RunJobs(ctx)
+	assert.ElementsMatch(t, []scheduler.Run{
+		{Job: JobOverdraftInterest, Day: today},
+		{Job: JobInterest, Day: today},
+		{Job: JobStandingOrders, Day: today},
+	}, runs)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, -200.2, acc.Balance)
+	assert.Empty(t, svc.RunJobs(ctx))
+
+	svc = New(WithRepository(repo))
+	assert.Empty(t, svc.RunJobs(ctx))
+	acc, _ = svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, -200.2, acc.Balance)
+	assert.Equal(t, []scheduler.Status{
+		{Job: JobInterest, LastDay: today},
+		{Job: JobOverdraftInterest, LastDay: today},
+		{Job: JobStandingOrders, LastDay: today},
+	}, svc.Jobs(ctx))
+}
+
+// run with -race : the jobs and the HTTP handlers change the same data
+func TestRunJobs_ConcurrentWithWithdraw(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	svc.SetOverdraft(ctx, "112233", 1000, 36.5)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for day := 1; day <= 365; day++ {
+			svc.TravelTo(ctx, monday.AddDate(0, 0, day))
+		}
+	}()
+	for i := 0; i < 60; i++ {
+		_, resp := svc.Withdraw(ctx, "112233", 10)
+		assert.Nil(t, resp)
+	}
+	wg.Wait()
+
+	report := svc.LedgerCheck(ctx)
+	assert.True(t, report.Balanced, report.Violations)
+	assert.Equal(t, []scheduler.Status{
+		{Job: JobInterest, LastDay: "2027-10-19"},
+		{Job: JobOverdraftInterest, LastDay: "2027-10-19"},
+		{Job: JobStandingOrders, LastDay: "2027-10-19"},
+	}, svc.Jobs(ctx))
+}
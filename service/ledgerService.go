@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+var gl *ledger.Ledger
+
+// initLedger opens the ledger with the balances of the initial accounts
+func (s *Service) initLedger() {
+	gl = ledger.New(s.clock)
+	for _, acc := range sortedAccounts() {
+		if acc.Balance != 0 {
+			postOpening(acc.AccountNumber, acc.Balance)
+		}
+	}
+}
+
+// postOpening opens the balance of an account that was never posted to
+func postOpening(acctNbr string, balance float64) {
+	gl.Post(ledger.OpeningEntry(acctNbr, balance))
+}
+
+// loadLedger replays a saved journal. Accounts that were never posted to, like accounts
+// saved before the ledger existed, are opened with the balance they were saved with.
+func (s *Service) loadLedger(journal []entity.JournalEntry) error {
+	gl = ledger.New(s.clock)
+	for _, e := range journal {
+		if _, err := gl.Post(e); err != nil {
+			return fmt.Errorf("journal entry %s : %w", e.ID, err)
+		}
+	}
+	for _, acc := range sortedAccounts() {
+		if !gl.HasPostings(ledger.CustomerAccount(acc.AccountNumber)) && acc.Balance != 0 {
+			postOpening(acc.AccountNumber, acc.Balance)
+		}
+		if b := gl.CustomerBalance(acc.AccountNumber); b != acc.Balance {
+			log.Printf("Balance of %s was saved as %.2f, the ledger says %.2f \n", acc.AccountNumber, acc.Balance, b)
+			acc.Balance = b
+		}
+	}
+	return nil
+}
+
+// post adds a journal entry and refreshes the balance of the customer accounts it moves
+func (s *Service) post(entry entity.JournalEntry) (*entity.JournalEntry, *responseFormatter.ResponseFormatter) {
+	if entry.Time.IsZero() {
+		entry.Time = s.clock.Now()
+	}
+	posted, err := gl.Post(entry)
+	if err != nil {
+		return nil, responseFormatter.New(http.StatusInternalServerError,
+			fmt.Sprintf("Failed posting journal entry : %s", err.Error()), true)
+	}
+	for _, p := range posted.Postings {
+		if acctNbr, ok := ledger.CustomerAccountNumber(p.Account); ok && accMap[acctNbr] != nil {
+			accMap[acctNbr].Balance = gl.CustomerBalance(acctNbr)
+		}
+	}
+	return posted, nil
+}
+
+// openAccount adds a new account, its initial balance is posted as an opening balance,
+// and issues it a card with the account PIN. Accounts without a tier or a type get the default ones.
+// The opening balance is posted first, so an account whose balance cannot be posted is not added.
+func (s *Service) openAccount(acc *entity.Account) *responseFormatter.ResponseFormatter {
+	if acc.Balance != 0 {
+		if _, resp := s.post(ledger.OpeningEntry(acc.AccountNumber, acc.Balance)); resp != nil {
+			return resp
+		}
+	}
+	acc.Balance = gl.CustomerBalance(acc.AccountNumber)
+	if acc.Tier == "" {
+		acc.Tier = entity.DefaultTier
+	}
+	if acc.Type == "" {
+		acc.Type = entity.DefaultAccountType
+	}
+	if acc.Currency == "" {
+		acc.Currency = fx.Base
+	}
+	accMap[acc.AccountNumber] = acc
+	s.issueCard(acc.PIN, acc.AccountNumber)
+	return nil
+}
+
+// LedgerCheck verifies that every journal entry balances to zero and
+// that every customer account balance is the balance derived from its postings
+func (s *Service) LedgerCheck(ctx context.Context) *entity.LedgerReport {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	report := &entity.LedgerReport{Violations: gl.Check(), Balances: gl.Balances()}
+	for _, b := range report.Balances {
+		report.Total += b
+	}
+	report.Total = float64(int64(report.Total*100+0.5)) / 100
+	if report.Total != 0 {
+		report.Violations = append(report.Violations, fmt.Sprintf("ledger accounts sum to %.2f instead of zero", report.Total))
+	}
+	for _, acc := range sortedAccounts() {
+		if b := gl.CustomerBalance(acc.AccountNumber); b != acc.Balance {
+			report.Violations = append(report.Violations,
+				fmt.Sprintf("balance of %s is %.2f, its postings sum to %.2f", acc.AccountNumber, acc.Balance, b))
+		}
+	}
+	if report.Violations == nil {
+		report.Violations = []string{}
+	}
+	report.Balanced = len(report.Violations) == 0
+	return report
+}
+
+func (s *Service) Journal(ctx context.Context) []entity.JournalEntry {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	return gl.Entries()
+}
+
+func sortedAccounts() []*entity.Account {
+	accounts := make([]*entity.Account, 0, len(accMap))
+	for _, acc := range accMap {
+		accounts = append(accounts, acc)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].AccountNumber < accounts[j].AccountNumber
+	})
+	return accounts
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger_EveryBalanceChangeIsPosted(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, resp := svc.Withdraw(ctx, "112233", 30)
+	assert.Nil(t, resp)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 20})
+	assert.Nil(t, resp)
+	_, resp = svc.CreateAccount(ctx, entity.Account{Name: "Richard Roe", AccountNumber: "112255", PIN: "123456", Balance: 75})
+	assert.Nil(t, resp)
+
+	journal := svc.Journal(ctx)
+	last := journal[len(journal)-1]
+	assert.Equal(t, "", last.TransactionID)
+	assert.Equal(t, ledger.OpeningBalance, last.Postings[0].Account)
+	assert.Equal(t, acc.TransactionID, journal[len(journal)-3].TransactionID)
+
+	report := svc.LedgerCheck(ctx)
+	assert.True(t, report.Balanced)
+	assert.Empty(t, report.Violations)
+	assert.Equal(t, 0.0, report.Total)
+	assert.Equal(t, -50.0, report.Balances[ledger.CustomerAccount("112233")])
+	assert.Equal(t, -120.0, report.Balances[ledger.CustomerAccount("112244")])
+	assert.Equal(t, -30.0, report.Balances[ledger.ATMCashAccount(DefaultATMID())])
+}
+
+func TestLedgerCheck_ReportsBalanceNotFromPostings(t *testing.T) {
+	svc := New()
+	accMap["112233"].Balance += 5
+	report := svc.LedgerCheck(context.Background())
+	assert.False(t, report.Balanced)
+	assert.Len(t, report.Violations, 1)
+	assert.True(t, strings.Contains(report.Violations[0], "112233"))
+}
+
+func TestLoad_OpensAccountsSavedWithoutJournal(t *testing.T) {
+	repo := repository.NewMemory()
+	repo.Save(&repository.Snapshot{Accounts: []entity.Account{
+		{Name: "Richard Roe", AccountNumber: "112255", PIN: "123456", Balance: 80, Status: entity.AccountActive},
+	}})
+	svc := New(WithRepository(repo))
+	ctx := context.Background()
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	_, resp := svc.Withdraw(ctx, "112255", 50)
+	assert.Nil(t, resp)
+	snapshot, _ := repo.Load()
+	assert.Len(t, snapshot.Journal, 2)
+
+	reloaded := New(WithRepository(repo))
+	acc, _ := reloaded.BalanceCheck(ctx, "112255")
+	assert.Equal(t, 30.0, acc.Balance)
+	assert.True(t, reloaded.LedgerCheck(ctx).Balanced)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+)
+
+var notificationList []*entity.Notification
+
+func initNotification() {
+	notificationList = nil
+}
+
+func (s *Service) notify(acctNbr, message string) {
+	notificationList = append(notificationList, &entity.Notification{
+		ID:            entity.FormatNotificationID(len(notificationList) + 1),
+		AccountNumber: acctNbr,
+		Message:       message,
+		CreatedAt:     s.clock.Now(),
+	})
+}
+
+// Notifications lists the notifications of an account, the latest first
+func (s *Service) Notifications(ctx context.Context, acctNbr string) []*entity.Notification {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	notifications := []*entity.Notification{}
+	for i := len(notificationList) - 1; i >= 0; i-- {
+		if notificationList[i].AccountNumber == acctNbr {
+			notifications = append(notifications, notificationList[i])
+		}
+	}
+	return notifications
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// overdraftLimit is how far below zero the balance of acc can go
+func overdraftLimit(acc *entity.Account) float64 {
+	if !accountTypeOf(acc).Overdraft {
+		return 0
+	}
+	return acc.OverdraftLimit
+}
+
+// spendableBalance is what withdrawals and transfers can take from acc, its overdraft included
+func (s *Service) spendableBalance(acc *entity.Account) float64 {
+	return s.availableBalance(acc) + overdraftLimit(acc)
+}
+
+// overdraftHeadroom is the part of the overdraft the available balance does not use yet
+func (s *Service) overdraftHeadroom(acc *entity.Account) float64 {
+	limit := overdraftLimit(acc)
+	return math.Max(0, math.Round((limit+math.Min(0, s.availableBalance(acc)))*100)/100)
+}
+
+// SetOverdraft opts a checking account in the overdraft facility with limit, interest is charged
+// every day at rate percent a year on a negative balance. A zero limit opts the account out.
+func (s *Service) SetOverdraft(ctx context.Context, acctNbr string, limit, rate float64) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	} else if accountTypeOf(acc).Type == entity.AccountSavings {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Overdraft is only available on checking accounts", true)
+	} else if limit < 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Overdraft limit cannot be negative", true)
+	} else if rate < 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Overdraft rate cannot be negative", true)
+	} else if limit < -acc.Balance {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Overdraft limit cannot be lower than the overdrawn balance", true)
+	}
+	acc.OverdraftLimit, acc.OverdraftRate = limit, rate
+	if limit > 0 {
+		acc.Type = entity.AccountCheckingOverdraft
+	} else {
+		acc.Type = entity.AccountChecking
+		acc.OverdraftRate = 0
+	}
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+// AccrueOverdraftInterest charges the interest of day to every account overdrawn at the end of that day.
+// When the jobs catch up on missed days, each day is charged on the balance of that day, not on today's.
+// An account is charged at most once a day, so running it again on the same day does nothing.
+func (s *Service) AccrueOverdraftInterest(ctx context.Context, day time.Time) []*entity.Transaction {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	date := day.Format("2006-01-02")
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+	charged := []*entity.Transaction{}
+	for _, acc := range sortedAccounts() {
+		if acc.OverdraftRate == 0 || acc.OverdraftInterestOn >= date {
+			continue
+		}
+		balance := gl.CustomerBalanceAt(acc.AccountNumber, endOfDay)
+		if balance >= 0 {
+			continue
+		}
+		interest := math.Round(-balance*acc.OverdraftRate/100/365*100) / 100
+		acc.OverdraftInterestOn = date
+		if interest == 0 {
+			continue
+		}
+		entry := ledger.OverdraftInterestEntry(acc.AccountNumber, interest)
+		entry.TransactionID = nextTransactionID()
+		if _, resp := s.post(entry); resp != nil {
+			log.Printf("Overdraft interest of %s : %s \n", acc.AccountNumber, resp.Message)
+			continue
+		}
+		charged = append(charged, s.recordTransaction(ctx, entity.Transaction{
+			ID:            entry.TransactionID,
+			ATMID:         systemATMID,
+			AccountNumber: acc.AccountNumber,
+			Type:          entity.TransactionOverdraftInterest,
+			Amount:        interest,
+		}))
+	}
+	s.save()
+	return charged
+}
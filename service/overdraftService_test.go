@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverdraft_WithdrawBelowZero(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.Withdraw(ctx, "112233", 150)
+	assert.Equal(t, "Insufficient balance $150", resp.Message)
+
+	acc, resp := svc.SetOverdraft(ctx, "112233", 200, 18.25)
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.AccountCheckingOverdraft, acc.Type)
+	assert.Equal(t, float64(200), acc.OverdraftHeadroom)
+
+	acc, resp = svc.Withdraw(ctx, "112233", 150)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(-50), acc.Balance)
+	assert.Equal(t, float64(150), acc.OverdraftHeadroom)
+
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 151})
+	assert.Equal(t, "Insufficient balance $151", resp.Message)
+	acc, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 150})
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(-200), acc.Balance)
+	assert.Equal(t, float64(0), acc.OverdraftHeadroom)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	_, resp = svc.SetOverdraft(ctx, "112233", 100, 0)
+	assert.Equal(t, "Overdraft limit cannot be lower than the overdrawn balance", resp.Message)
+}
+
+func TestOverdraft_OptInRules(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	_, resp := svc.CreateAccount(ctx, entity.Account{
+		Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 100, Type: entity.AccountSavings,
+	})
+	assert.Nil(t, resp)
+	_, resp = svc.SetOverdraft(ctx, "445566", 100, 10)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp = svc.SetOverdraft(ctx, "112233", -1, 10)
+	assert.Equal(t, "Overdraft limit cannot be negative", resp.Message)
+	_, resp = svc.CreateAccount(ctx, entity.Account{
+		Name: "Bob", AccountNumber: "445577", PIN: "445577", OverdraftLimit: 100,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	svc.SetOverdraft(ctx, "112233", 200, 10)
+	acc, resp := svc.SetOverdraft(ctx, "112233", 0, 10)
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.AccountChecking, acc.Type)
+	assert.Zero(t, acc.OverdraftLimit)
+}
+
+func TestOverdraft_DailyInterest(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	svc.SetOverdraft(ctx, "112233", 1000, 36.5)
+	svc.Withdraw(ctx, "112233", 1000)
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	charged := svc.AccrueOverdraftInterest(ctx, day)
+	assert.Len(t, charged, 1)
+	assert.Equal(t, entity.TransactionOverdraftInterest, charged[0].Type)
+	assert.Equal(t, 0.9, charged[0].Amount)
+	assert.Equal(t, -900.9, charged[0].Balance)
+
+	assert.Empty(t, svc.AccrueOverdraftInterest(ctx, day))
+	assert.Len(t, svc.AccrueOverdraftInterest(ctx, day.AddDate(0, 0, 1)), 1)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, -901.8, acc.Balance)
+}
+
+func TestOverdraft_CatchUpChargesBalanceOfEachDay(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	svc.SetOverdraft(ctx, "112233", 200, 36.5)
+	svc.Withdraw(ctx, "112233", 200)
+	c.Advance(3 * 24 * time.Hour)
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112244", ToAccountNumber: "112233", Amount: 100})
+	assert.Nil(t, resp)
+
+	charged := svc.AccrueOverdraftInterest(ctx, monday.AddDate(0, 0, 1))
+	if assert.Len(t, charged, 1) {
+		assert.Equal(t, 0.1, charged[0].Amount)
+	}
+	assert.Len(t, svc.AccrueOverdraftInterest(ctx, monday.AddDate(0, 0, 2)), 1)
+	assert.Empty(t, svc.AccrueOverdraftInterest(ctx, monday.AddDate(0, 0, 3)))
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, -0.2, acc.Balance)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/ledger"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// Reverse gives back amount of a withdrawal or of an outgoing transfer, all of the amount
+// not reversed yet when amount is 0. A transaction can be reversed in parts, like the cash
+// a jammed dispenser did not hand out, until all of it is reversed.
+func (s *Service) Reverse(ctx context.Context, trxID string, amount float64, reason string) (*entity.Transaction, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	trx := trxMap[trxID]
+	if trx == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Transaction not found", true)
+	} else if trx.Type != entity.TransactionWithdraw && trx.Type != entity.TransactionTransferOut {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Only withdrawals and outgoing transfers can be reversed", true)
+	} else if trx.CounterpartBankCode != "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Transfers to other banks cannot be reversed", true)
+	}
+	remaining := math.Round((trx.Amount-trx.ReversedAmount)*100) / 100
+	if remaining <= 0 {
+		return nil, responseFormatter.New(http.StatusConflict, "Transaction is already reversed", true).
+			WithCode(ErrCodeAlreadyReversed)
+	}
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 0 {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid reversal amount", true)
+	} else if amount > remaining {
+		return nil, responseFormatter.New(http.StatusBadRequest,
+			fmt.Sprintf("Reversal amount cannot be more than the %s not reversed yet", money(accMap[trx.AccountNumber], remaining)), true)
+	} else if accMap[trx.AccountNumber].Status == entity.AccountClosed {
+		return nil, responseFormatter.New(http.StatusConflict, "Account is closed", true).WithCode(ErrCodeAccountClosed)
+	}
+
+	var entry entity.JournalEntry
+	var trxIn *entity.Transaction
+	// destAmount is what the destination of a transfer gives back, in its own currency
+	destAmount := amount
+	switch trx.Type {
+	case entity.TransactionWithdraw:
+		entry = ledger.ReversalEntry(ledger.WithdrawEntry(trx.AccountNumber, trx.ATMID, amount))
+	case entity.TransactionTransferOut:
+		trxIn = transferIn(trx)
+		dest := accMap[trx.CounterpartAccountNumber]
+		if trx.FXRate != 0 {
+			destAmount = fx.Conversion{Rate: trx.FXRate}.Convert(amount)
+			if trx.ReversedAmount == 0 && amount == trx.Amount {
+				destAmount = trx.CounterpartAmount
+			}
+		}
+		if dest.Status == entity.AccountClosed {
+			return nil, responseFormatter.New(http.StatusConflict, "Destination account is closed", true).
+				WithCode(ErrCodeDestinationUnavailable)
+		} else if s.availableBalance(dest) < destAmount {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Destination account has insufficient balance to reverse the transfer", true)
+		}
+		entry = ledger.ReversalEntry(ledger.TransferEntry(trx.AccountNumber, trx.CounterpartAccountNumber, amount))
+		if trx.FXRate != 0 {
+			entry = ledger.ReversalEntry(ledger.FXTransferEntry(trx.AccountNumber, currencyOf(accMap[trx.AccountNumber]), amount,
+				dest.AccountNumber, currencyOf(dest), destAmount))
+		}
+	}
+	reversal, resp := s.recordReversal(ctx, trx, amount, reason, entry)
+	if resp != nil {
+		return nil, resp
+	}
+	if trx.Type == entity.TransactionWithdraw {
+		// the cash that was not handed out is still in the ATM
+		if atm := atmMap[trx.ATMID]; atm != nil {
+			atm.Cash += amount
+		}
+	} else {
+		s.recordTransaction(ctx, entity.Transaction{
+			ATMID:                    trx.ATMID,
+			AccountNumber:            trx.CounterpartAccountNumber,
+			Type:                     entity.TransactionReversalOut,
+			Amount:                   destAmount,
+			CounterpartAccountNumber: trx.AccountNumber,
+			ReferenceNumber:          trx.ReferenceNumber,
+			ReversalOf:               trx.ID,
+			Reason:                   reason,
+		})
+		if trxIn != nil {
+			markReversed(trxIn, destAmount)
+		}
+	}
+	s.save()
+	return reversal, nil
+}
+
+// recordReversal posts entry, which gives amount of trx back, and records it as a REVERSAL_IN
+func (s *Service) recordReversal(ctx context.Context, trx *entity.Transaction, amount float64, reason string, entry entity.JournalEntry) (*entity.Transaction, *responseFormatter.ResponseFormatter) {
+	entry.TransactionID = nextTransactionID()
+	if _, resp := s.post(entry); resp != nil {
+		return nil, resp
+	}
+	reversal := s.recordTransaction(ctx, entity.Transaction{
+		ID:                       entry.TransactionID,
+		ATMID:                    trx.ATMID,
+		AccountNumber:            trx.AccountNumber,
+		Type:                     entity.TransactionReversalIn,
+		Amount:                   amount,
+		CounterpartAccountNumber: trx.CounterpartAccountNumber,
+		ReferenceNumber:          trx.ReferenceNumber,
+		ReversalOf:               trx.ID,
+		Reason:                   reason,
+	})
+	markReversed(trx, amount)
+	return reversal, nil
+}
+
+func markReversed(trx *entity.Transaction, amount float64) {
+	trx.ReversedAmount = math.Round((trx.ReversedAmount+amount)*100) / 100
+	if trx.ReversedAmount < trx.Amount {
+		trx.ReversalStatus = entity.ReversalPartial
+	} else {
+		trx.ReversalStatus = entity.ReversalFull
+	}
+}
+
+// transferIn finds the TRANSFER_IN recorded right after out
+func transferIn(out *entity.Transaction) *entity.Transaction {
+	for i, trx := range trxList {
+		if trx != out {
+			continue
+		}
+		if i+1 < len(trxList) && trxList[i+1].Type == entity.TransactionTransferIn &&
+			trxList[i+1].CounterpartAccountNumber == out.AccountNumber {
+			return trxList[i+1]
+		}
+		break
+	}
+	return nil
+}
+
+// Transactions lists the transactions of an account, oldest first, with their reversal status
+func (s *Service) Transactions(ctx context.Context, acctNbr string) ([]*entity.Transaction, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	}
+	trxs := []*entity.Transaction{}
+	for _, trx := range trxList {
+		if trx.AccountNumber == acctNbr {
+			trxs = append(trxs, trx)
+		}
+	}
+	return trxs, nil
+}
+
+func (s *Service) GetTransaction(ctx context.Context, trxID string) (*entity.Transaction, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	trx := trxMap[trxID]
+	if trx == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Transaction not found", true)
+	}
+	return trx, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverse_Withdraw(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	cash := atmMap[DefaultATMID()].Cash
+	acc, _ := svc.Withdraw(ctx, "112233", 50)
+
+	reversal, resp := svc.Reverse(ctx, acc.TransactionID, 0, "dispenser jammed")
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.TransactionReversalIn, reversal.Type)
+	assert.Equal(t, acc.TransactionID, reversal.ReversalOf)
+	assert.Equal(t, float64(100), reversal.Balance)
+	assert.Equal(t, cash, atmMap[DefaultATMID()].Cash)
+
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, entity.ReversalFull, trx.ReversalStatus)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+
+	_, resp = svc.Reverse(ctx, acc.TransactionID, 0, "again")
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, ErrCodeAlreadyReversed, resp.Code)
+}
+
+func TestReverse_PartialDispense(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 80)
+
+	_, resp := svc.Reverse(ctx, acc.TransactionID, 30, "only $50 dispensed")
+	assert.Nil(t, resp)
+	trx, _ := svc.GetTransaction(ctx, acc.TransactionID)
+	assert.Equal(t, entity.ReversalPartial, trx.ReversalStatus)
+	assert.Equal(t, float64(30), trx.ReversedAmount)
+
+	_, resp = svc.Reverse(ctx, acc.TransactionID, 60, "too much")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Reversal amount cannot be more than the $50.00 not reversed yet", resp.Message)
+
+	_, resp = svc.Reverse(ctx, acc.TransactionID, 0, "rest of it")
+	assert.Nil(t, resp)
+	balance, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(100), balance.Balance)
+	assert.Equal(t, entity.ReversalFull, trx.ReversalStatus)
+}
+
+func TestReverse_Transfer(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 40})
+
+	_, resp := svc.Reverse(ctx, acc.TransactionID, 0, "wrong destination")
+	assert.Nil(t, resp)
+	from, _ := svc.BalanceCheck(ctx, "112233")
+	to, _ := svc.BalanceCheck(ctx, "112244")
+	assert.Equal(t, float64(100), from.Balance)
+	assert.Equal(t, float64(100), to.Balance)
+
+	trxs, _ := svc.Transactions(ctx, "112244")
+	assert.Len(t, trxs, 2)
+	assert.Equal(t, entity.ReversalFull, trxs[0].ReversalStatus)
+	assert.Equal(t, entity.TransactionReversalOut, trxs[1].Type)
+	assert.True(t, svc.LedgerCheck(ctx).Balanced)
+}
+
+func TestReverse_TransferAlreadySpent(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 40})
+	svc.Withdraw(ctx, "112244", 130)
+
+	_, resp := svc.Reverse(ctx, acc.TransactionID, 0, "wrong destination")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp = svc.Reverse(ctx, acc.TransactionID, 10, "wrong destination")
+	assert.Nil(t, resp)
+}
+
+func TestReverse_OnlyDebits(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112233", ToAccountNumber: "112244", Amount: 40})
+	trxs, _ := svc.Transactions(ctx, "112244")
+
+	_, resp := svc.Reverse(ctx, trxs[0].ID, 0, "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp = svc.Reverse(ctx, "TRX99999999", 0, "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -2,21 +2,293 @@ package service
 
 import (
 	"context"
+	"log"
+	"sort"
+	"sync"
 
+	"github.com/fazarmitrais/atm-simulation/biller"
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/device"
 	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fee"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/interbank"
+	"github.com/fazarmitrais/atm-simulation/interest"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
 	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
 )
 
 type Service struct {
+	// mu serializes the operations of the service, the HTTP handlers and the batch jobs share its data
+	mu           sync.Mutex
+	clock        clock.Clock
+	repo         repository.Repository
+	dispenser    device.Dispenser
+	fees         *fee.Engine
+	interest     *interest.Engine
+	jobs         *scheduler.Scheduler
+	billers      *biller.Registry
+	billerClient biller.Client
+	network      *interbank.Switch
+	fx           *fx.Provider
 }
 
-func New() *Service {
+type Option func(*Service)
+
+type lockContextKey struct{}
+
+// lock takes the lock of the service for the operation of ctx. An operation called by another one,
+// like the transfer of a standing order, runs under the lock its caller holds : the returned context
+// tells that the lock is held, and unlock is then a no-op.
+func (s *Service) lock(ctx context.Context) (context.Context, func()) {
+	if ctx.Value(lockContextKey{}) == s {
+		return ctx, func() {}
+	}
+	s.mu.Lock()
+	return context.WithValue(ctx, lockContextKey{}, s), s.mu.Unlock
+}
+
+// WithRepository keeps the accounts and transactions in repo instead of in memory only
+func WithRepository(repo repository.Repository) Option {
+	return func(s *Service) {
+		s.repo = repo
+	}
+}
+
+// WithDispenser hands out the cash of withdrawals with dispenser instead of a simulated dispenser
+func WithDispenser(dispenser device.Dispenser) Option {
+	return func(s *Service) {
+		s.dispenser = dispenser
+	}
+}
+
+// WithFees charges the fees of engine instead of the fees configured in the FEE_CONFIG file
+func WithFees(engine *fee.Engine) Option {
+	return func(s *Service) {
+		s.fees = engine
+	}
+}
+
+// WithInterest pays the interest of engine instead of the interest configured in the INTEREST_CONFIG file
+func WithInterest(engine *interest.Engine) Option {
+	return func(s *Service) {
+		s.interest = engine
+	}
+}
+
+// WithBillers lets customers pay the billers of registry instead of the billers of the BILLER_CONFIG file
+func WithBillers(registry *biller.Registry) Option {
+	return func(s *Service) {
+		s.billers = registry
+	}
+}
+
+// WithBillerClient sends bill inquiries and payments to client instead of a stub biller service
+func WithBillerClient(client biller.Client) Option {
+	return func(s *Service) {
+		s.billerClient = client
+	}
+}
+
+// WithFX converts transfers between currencies at the rates of provider instead of the rates of the FX_CONFIG file
+func WithFX(provider *fx.Provider) Option {
+	return func(s *Service) {
+		s.fx = provider
+	}
+}
+
+// WithNetwork sends the transfers to other banks through network instead of a switch to the simulated banks.
+// The service joins network as the bank of BANK_CODE.
+func WithNetwork(network *interbank.Switch) Option {
+	return func(s *Service) {
+		s.network = network
+	}
+}
+
+// WithClock makes the service tell time with c instead of the machine clock
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+func New(opts ...Option) *Service {
+	s := &Service{clock: clock.Real(), repo: repository.NewMemory(), dispenser: device.NewSimulated(), billerClient: biller.NewStub()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.fees == nil {
+		fees, err := fee.Load(envLib.GetEnv("FEE_CONFIG"))
+		if err != nil {
+			log.Fatalf("Failed loading fee rules : %s \n", err.Error())
+		}
+		s.fees = fees
+	}
+	if s.interest == nil {
+		rates, err := interest.Load(envLib.GetEnv("INTEREST_CONFIG"))
+		if err != nil {
+			log.Fatalf("Failed loading interest rules : %s \n", err.Error())
+		}
+		s.interest = rates
+	}
+	if s.billers == nil {
+		billers, err := biller.Load(envLib.GetEnv("BILLER_CONFIG"))
+		if err != nil {
+			log.Fatalf("Failed loading billers : %s \n", err.Error())
+		}
+		s.billers = billers
+	}
+	if s.fx == nil {
+		rates, err := fx.Load(envLib.GetEnv("FX_CONFIG"), s.clock)
+		if err != nil {
+			log.Fatalf("Failed loading exchange rates : %s \n", err.Error())
+		}
+		s.fx = rates
+	}
+	if s.network == nil {
+		s.network = defaultNetwork()
+	}
+	s.network.Register(&localBank{s: s})
 	initData()
-	return &Service{}
+	initATM()
+	initTransaction()
+	s.initLedger()
+	initDispute()
+	initHold()
+	initCard()
+	initStandingOrder()
+	initNotification()
+	initBeneficiary()
+	initFXQuote()
+	initCardless()
+	s.initJobs()
+	s.load()
+	s.issueMissingCards()
+	for _, v := range s.LedgerCheck(context.Background()).Violations {
+		log.Printf("Ledger check : %s \n", v)
+	}
+	return s
 }
 
 type ServiceInterface interface {
-	PINValidation(c context.Context, account entity.Account) *responseFormatter.ResponseFormatter
+	PINValidation(c context.Context, login entity.CardLogin) (*entity.AccountResponse, *responseFormatter.ResponseFormatter)
 	Transfer(ctx, transfer entity.Transfer) (*entity.Account, *responseFormatter.ResponseFormatter)
 	BalanceCheck(ctx context.Context, acctNbr string) (*entity.Account, *responseFormatter.ResponseFormatter)
 }
+
+// load replaces the initial data with the data saved in the repository, if any
+func (s *Service) load() {
+	snapshot, err := s.repo.Load()
+	if err != nil {
+		log.Fatalf("Failed loading repository : %s \n", err.Error())
+	}
+	if snapshot == nil {
+		return
+	}
+	accMap = make(map[string]*entity.Account)
+	for i := range snapshot.Accounts {
+		accMap[snapshot.Accounts[i].AccountNumber] = &snapshot.Accounts[i]
+	}
+	for i := range snapshot.Transactions {
+		trx := &snapshot.Transactions[i]
+		trxList = append(trxList, trx)
+		trxMap[trx.ID] = trx
+	}
+	trxSeq = len(trxList)
+	for i := range snapshot.Disputes {
+		d := &snapshot.Disputes[i]
+		disputeList = append(disputeList, d)
+		disputeMap[d.ID] = d
+	}
+	for i := range snapshot.Holds {
+		h := &snapshot.Holds[i]
+		holdList = append(holdList, h)
+		holdMap[h.ID] = h
+	}
+	s.jobs.Restore(snapshot.JobRuns)
+	for i := range snapshot.Cards {
+		c := &snapshot.Cards[i]
+		cardList = append(cardList, c)
+		cardMap[c.Number] = c
+	}
+	for i := range snapshot.StandingOrders {
+		o := &snapshot.StandingOrders[i]
+		standingOrderList = append(standingOrderList, o)
+		standingOrderMap[o.ID] = o
+	}
+	for i := range snapshot.Notifications {
+		notificationList = append(notificationList, &snapshot.Notifications[i])
+	}
+	for i := range snapshot.Beneficiaries {
+		b := &snapshot.Beneficiaries[i]
+		beneficiaryList = append(beneficiaryList, b)
+		beneficiaryMap[b.ID] = b
+	}
+	for i := range snapshot.CardlessWithdrawals {
+		addCardless(&snapshot.CardlessWithdrawals[i])
+	}
+	if err := s.loadLedger(snapshot.Journal); err != nil {
+		log.Fatalf("Failed loading journal : %s \n", err.Error())
+	}
+}
+
+// save writes the current data to the repository. The change is already done in memory,
+// so a failure is only logged.
+func (s *Service) save() {
+	snapshot := &repository.Snapshot{
+		Accounts:            make([]entity.Account, 0, len(accMap)),
+		Transactions:        make([]entity.Transaction, 0, len(trxList)),
+		Journal:             gl.Entries(),
+		Disputes:            make([]entity.Dispute, 0, len(disputeList)),
+		Holds:               make([]entity.Hold, 0, len(holdList)),
+		Cards:               make([]entity.Card, 0, len(cardList)),
+		JobRuns:             s.jobs.LastDays(),
+		StandingOrders:      make([]entity.StandingOrder, 0, len(standingOrderList)),
+		Notifications:       make([]entity.Notification, 0, len(notificationList)),
+		Beneficiaries:       make([]entity.Beneficiary, 0, len(beneficiaryList)),
+		CardlessWithdrawals: make([]entity.CardlessWithdrawal, 0, len(cardlessList)),
+	}
+	for _, acc := range accMap {
+		acc := *acc
+		if acc.TOTP != nil {
+			// the one-time password state keeps changing in memory, the snapshot gets its own copy
+			f := *acc.TOTP
+			acc.TOTP = &f
+		}
+		snapshot.Accounts = append(snapshot.Accounts, acc)
+	}
+	sort.Slice(snapshot.Accounts, func(i, j int) bool {
+		return snapshot.Accounts[i].AccountNumber < snapshot.Accounts[j].AccountNumber
+	})
+	for _, trx := range trxList {
+		snapshot.Transactions = append(snapshot.Transactions, *trx)
+	}
+	for _, d := range disputeList {
+		snapshot.Disputes = append(snapshot.Disputes, *d)
+	}
+	for _, h := range holdList {
+		snapshot.Holds = append(snapshot.Holds, *h)
+	}
+	for _, c := range cardList {
+		snapshot.Cards = append(snapshot.Cards, *c)
+	}
+	for _, o := range standingOrderList {
+		o := *o
+		o.Executions = append([]entity.StandingOrderRun{}, o.Executions...)
+		snapshot.StandingOrders = append(snapshot.StandingOrders, o)
+	}
+	for _, n := range notificationList {
+		snapshot.Notifications = append(snapshot.Notifications, *n)
+	}
+	for _, b := range beneficiaryList {
+		snapshot.Beneficiaries = append(snapshot.Beneficiaries, *b)
+	}
+	for _, w := range cardlessList {
+		snapshot.CardlessWithdrawals = append(snapshot.CardlessWithdrawals, *w)
+	}
+	if err := s.repo.Save(snapshot); err != nil {
+		log.Printf("Failed saving repository : %s \n", err.Error())
+	}
+}
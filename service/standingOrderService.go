@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/scheduler"
+)
+
+// standingOrderRetryDays is how many days after its due day a payment is retried
+// when the balance is insufficient
+const standingOrderRetryDays = 3
+
+var (
+	standingOrderList []*entity.StandingOrder
+	standingOrderMap  = make(map[string]*entity.StandingOrder)
+)
+
+func initStandingOrder() {
+	standingOrderList = nil
+	standingOrderMap = make(map[string]*entity.StandingOrder)
+}
+
+// CreateStandingOrder sets up a transfer from acctNbr repeated every week or month. The first payment
+// is on the first due day after today. The balance is only checked when a payment is due.
+func (s *Service) CreateStandingOrder(ctx context.Context, acctNbr string, order entity.StandingOrder) (*entity.StandingOrder, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	from, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if resp := checkAccountStatus(from); resp != nil {
+		return nil, resp
+	}
+	if order.Frequency == "" {
+		order.Frequency = entity.StandingOrderMonthly
+	}
+	to := accMap[order.ToAccountNumber]
+	switch {
+	case order.ToAccountNumber == "":
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	case order.ToAccountNumber == acctNbr:
+		return nil, responseFormatter.New(http.StatusBadRequest, "From and Destination account number cannot be the same", true)
+	case to == nil:
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	case to.Status == entity.AccountFrozen || to.Status == entity.AccountClosed:
+		return nil, responseFormatter.New(http.StatusBadRequest, "Destination account cannot receive transfers", true).
+			WithCode(ErrCodeDestinationUnavailable)
+	case order.Frequency != entity.StandingOrderWeekly && order.Frequency != entity.StandingOrderMonthly:
+		return nil, responseFormatter.New(http.StatusBadRequest, "Frequency should be WEEKLY or MONTHLY", true)
+	case order.Frequency == entity.StandingOrderWeekly && (order.Day < 1 || order.Day > 7):
+		return nil, responseFormatter.New(http.StatusBadRequest, "Day of a WEEKLY order should be between 1 (Monday) and 7 (Sunday)", true)
+	case order.Frequency == entity.StandingOrderMonthly && (order.Day < 1 || order.Day > 31):
+		return nil, responseFormatter.New(http.StatusBadRequest, "Day of a MONTHLY order should be between 1 and 31", true)
+	}
+	if resp := s.checkTransferAmount(from, order.Amount); resp != nil {
+		return nil, resp
+	} else if resp := s.checkTransferRules(from, to, 0, 0); resp != nil {
+		return nil, resp
+	} else if strings.Trim(order.ReferenceNumber, " ") != "" {
+		if _, err := strconv.Atoi(order.ReferenceNumber); err != nil {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid Reference Number", true)
+		}
+	}
+	if resp := s.checkStepUp(ctx, from, s.transferStepUp(from, BankCode(), order.ToAccountNumber, order.Amount)); resp != nil {
+		return nil, resp
+	}
+	now := s.clock.Now()
+	if order.EndDate != "" {
+		end, err := time.ParseInLocation(scheduler.DateFormat, order.EndDate, now.Location())
+		if err != nil {
+			return nil, responseFormatter.New(http.StatusBadRequest, "Invalid end date, use YYYY-MM-DD format", true)
+		} else if end.Format(scheduler.DateFormat) <= now.Format(scheduler.DateFormat) {
+			return nil, responseFormatter.New(http.StatusBadRequest, "End date should be after today", true)
+		}
+	}
+	o := &entity.StandingOrder{
+		ID:                entity.FormatStandingOrderID(len(standingOrderList) + 1),
+		FromAccountNumber: acctNbr,
+		ToAccountNumber:   order.ToAccountNumber,
+		Amount:            order.Amount,
+		ReferenceNumber:   order.ReferenceNumber,
+		Frequency:         order.Frequency,
+		Day:               order.Day,
+		EndDate:           order.EndDate,
+		Status:            entity.StandingOrderActive,
+		Executions:        []entity.StandingOrderRun{},
+		CreatedAt:         now,
+	}
+	standingOrderList = append(standingOrderList, o)
+	standingOrderMap[o.ID] = o
+	s.save()
+	return o, nil
+}
+
+// StandingOrders lists the standing orders of an account, only the active ones unless all is set
+func (s *Service) StandingOrders(ctx context.Context, acctNbr string, all bool) ([]*entity.StandingOrder, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if _, resp := findAccount(acctNbr); resp != nil {
+		return nil, resp
+	}
+	orders := []*entity.StandingOrder{}
+	for _, o := range standingOrderList {
+		if o.FromAccountNumber == acctNbr && (all || o.Status == entity.StandingOrderActive) {
+			orders = append(orders, o)
+		}
+	}
+	return orders, nil
+}
+
+// CancelStandingOrder stops the standing order orderID of acctNbr, a payment being retried is not made
+func (s *Service) CancelStandingOrder(ctx context.Context, acctNbr, orderID string) (*entity.StandingOrder, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	o := standingOrderMap[orderID]
+	if o == nil || o.FromAccountNumber != acctNbr {
+		return nil, responseFormatter.New(http.StatusNotFound, "Standing order not found", true)
+	} else if o.Status != entity.StandingOrderActive {
+		return nil, responseFormatter.New(http.StatusConflict, "Standing order is already cancelled or completed", true)
+	}
+	now := s.clock.Now()
+	o.Status = entity.StandingOrderCancelled
+	o.CancelledAt = &now
+	o.DueOn = ""
+	s.save()
+	return o, nil
+}
+
+// RunStandingOrders pays the standing orders due on day, and retries the payments that failed for
+// insufficient balance. Running a day twice pays nothing more.
+func (s *Service) RunStandingOrders(ctx context.Context, day time.Time) []entity.StandingOrderRun {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	runs := []entity.StandingOrderRun{}
+	date := day.Format(scheduler.DateFormat)
+	for _, o := range standingOrderList {
+		if o.Status != entity.StandingOrderActive {
+			continue
+		} else if n := len(o.Executions); n > 0 && o.Executions[n-1].Day >= date {
+			continue
+		}
+		if o.DueOn == "" && date > o.CreatedAt.Format(scheduler.DateFormat) &&
+			(o.EndDate == "" || date <= o.EndDate) && standingOrderDue(o, day) {
+			o.DueOn = date
+		}
+		if o.DueOn != "" {
+			runs = append(runs, s.payStandingOrder(ctx, o, day))
+		}
+		if o.DueOn == "" && o.EndDate != "" && date >= o.EndDate {
+			o.Status = entity.StandingOrderCompleted
+		}
+	}
+	return runs
+}
+
+func (s *Service) payStandingOrder(ctx context.Context, o *entity.StandingOrder, day time.Time) entity.StandingOrderRun {
+	run := entity.StandingOrderRun{DueOn: o.DueOn, Day: day.Format(scheduler.DateFormat), Time: s.clock.Now()}
+	// the customer authorised the payments when creating the order
+	acc, resp := s.Transfer(preAuthorized(WithATM(ctx, systemATMID)), entity.Transfer{
+		FromAccountNumber: o.FromAccountNumber,
+		ToAccountNumber:   o.ToAccountNumber,
+		ReferenceNumber:   o.ReferenceNumber,
+		Amount:            o.Amount,
+	})
+	if resp == nil {
+		run.TransactionID = acc.TransactionID
+		o.DueOn = ""
+		o.Executions = append(o.Executions, run)
+		return run
+	}
+	run.Error = resp.Message
+	o.Executions = append(o.Executions, run)
+	due, _ := time.ParseInLocation(scheduler.DateFormat, o.DueOn, day.Location())
+	lastTry := due.AddDate(0, 0, standingOrderRetryDays)
+	if resp.Code == ErrCodeInsufficientBalance && day.Before(lastTry) {
+		if run.Day == run.DueOn {
+			s.notify(o.FromAccountNumber, fmt.Sprintf("Standing order %s of %s to %s could not be paid : %s. It is retried daily until %s",
+				o.ID, fx.FormatWhole(currencyOf(accMap[o.FromAccountNumber]), o.Amount), o.ToAccountNumber, resp.Message, lastTry.Format(scheduler.DateFormat)))
+		}
+		return run
+	}
+	s.notify(o.FromAccountNumber, fmt.Sprintf("Standing order %s of %s to %s due on %s failed : %s",
+		o.ID, fx.FormatWhole(currencyOf(accMap[o.FromAccountNumber]), o.Amount), o.ToAccountNumber, o.DueOn, resp.Message))
+	o.DueOn = ""
+	return run
+}
+
+// standingOrderDue reports whether day is a payment day of o
+func standingOrderDue(o *entity.StandingOrder, day time.Time) bool {
+	if o.Frequency == entity.StandingOrderWeekly {
+		weekday := int(day.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		return weekday == o.Day
+	}
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if o.Day > lastDay {
+		return day.Day() == lastDay
+	}
+	return day.Day() == o.Day
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStandingOrder_Validation(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	tests := []struct {
+		order   entity.StandingOrder
+		message string
+	}{
+		{entity.StandingOrder{ToAccountNumber: "112233", Amount: 10, Day: 1}, "From and Destination account number cannot be the same"},
+		{entity.StandingOrder{ToAccountNumber: "999999", Amount: 10, Day: 1}, "Invalid account"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 32}, "Day of a MONTHLY order should be between 1 and 31"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 0, Frequency: entity.StandingOrderWeekly}, "Day of a WEEKLY order should be between 1 (Monday) and 7 (Sunday)"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 1, Frequency: "DAILY"}, "Frequency should be WEEKLY or MONTHLY"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 1001, Day: 1}, "Maximum amount to transfer is $1000"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 1, EndDate: "2026-10-19"}, "End date should be after today"},
+		{entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 1, EndDate: "19/10/2026"}, "Invalid end date, use YYYY-MM-DD format"},
+	}
+	for _, tt := range tests {
+		_, resp := svc.CreateStandingOrder(ctx, "112233", tt.order)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, tt.message, resp.Message)
+		}
+	}
+}
+
+func TestStandingOrder_PaysOnDueDay(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	o, resp := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{ToAccountNumber: "112244", Amount: 30, Day: 20})
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.StandingOrderMonthly, o.Frequency)
+
+	svc.RunJobs(ctx)
+	assert.Empty(t, o.Executions)
+	c.Advance(24 * time.Hour)
+	svc.RunJobs(ctx)
+	assert.Empty(t, svc.RunStandingOrders(ctx, c.Now()))
+	if assert.Len(t, o.Executions, 1) {
+		assert.Equal(t, "2026-10-20", o.Executions[0].DueOn)
+		assert.NotEmpty(t, o.Executions[0].TransactionID)
+	}
+	acc, _ := svc.BalanceCheck(ctx, "112233")
+	assert.Equal(t, float64(70), acc.Balance)
+	trx, _ := svc.GetTransaction(ctx, o.Executions[0].TransactionID)
+	assert.Equal(t, systemATMID, trx.ATMID)
+}
+
+func TestStandingOrder_RetriesInsufficientBalance(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	o, _ := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{ToAccountNumber: "112244", Amount: 150, Day: 20})
+	late, _ := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{ToAccountNumber: "112244", Amount: 500, Day: 20})
+
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	svc.RunStandingOrders(ctx, day)
+	assert.Equal(t, "2026-10-20", o.DueOn)
+	svc.RunStandingOrders(ctx, day.AddDate(0, 0, 1))
+	svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112244", ToAccountNumber: "112233", Amount: 100})
+	svc.RunStandingOrders(ctx, day.AddDate(0, 0, 2))
+	svc.RunStandingOrders(ctx, day.AddDate(0, 0, 3))
+
+	assert.Len(t, o.Executions, 3)
+	assert.Empty(t, o.DueOn)
+	assert.NotEmpty(t, o.Executions[2].TransactionID)
+	assert.Len(t, late.Executions, 4)
+	assert.Empty(t, late.DueOn)
+	assert.Equal(t, entity.StandingOrderActive, late.Status)
+
+	notifications := svc.Notifications(ctx, "112233")
+	if assert.Len(t, notifications, 3) {
+		assert.Equal(t, "Standing order STO00000002 of $500 to 112244 due on 2026-10-20 failed : Insufficient balance $500", notifications[0].Message)
+	}
+}
+
+func TestStandingOrder_CancelAndEndDate(t *testing.T) {
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c))
+	ctx := context.Background()
+	weekly, _ := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{
+		ToAccountNumber: "112244", Amount: 10, Frequency: entity.StandingOrderWeekly, Day: 1, EndDate: "2026-11-02"})
+	monthly, _ := svc.CreateStandingOrder(ctx, "112233", entity.StandingOrder{ToAccountNumber: "112244", Amount: 10, Day: 31})
+
+	_, resp := svc.CancelStandingOrder(ctx, "112244", monthly.ID)
+	assert.Equal(t, "Standing order not found", resp.Message)
+	_, resp = svc.CancelStandingOrder(ctx, "112233", monthly.ID)
+	assert.Nil(t, resp)
+	_, resp = svc.CancelStandingOrder(ctx, "112233", monthly.ID)
+	assert.Equal(t, "Standing order is already cancelled or completed", resp.Message)
+
+	svc.RunJobs(ctx)
+	svc.TravelTo(ctx, monday.AddDate(0, 0, 21))
+	assert.Len(t, weekly.Executions, 2)
+	assert.Equal(t, entity.StandingOrderCompleted, weekly.Status)
+	assert.Empty(t, monthly.Executions)
+	orders, _ := svc.StandingOrders(ctx, "112233", false)
+	assert.Empty(t, orders)
+	orders, _ = svc.StandingOrders(ctx, "112233", true)
+	assert.Len(t, orders, 2)
+}
+
+func TestStandingOrderDue_ShortMonth(t *testing.T) {
+	o := &entity.StandingOrder{Frequency: entity.StandingOrderMonthly, Day: 31}
+	assert.True(t, standingOrderDue(o, time.Date(2027, 2, 28, 0, 0, 0, 0, time.Local)))
+	assert.False(t, standingOrderDue(o, time.Date(2027, 3, 28, 0, 0, 0, 0, time.Local)))
+	assert.True(t, standingOrderDue(o, time.Date(2027, 3, 31, 0, 0, 0, 0, time.Local)))
+}
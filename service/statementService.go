@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+// Statement lists the transactions of an account from the start of day from until the end of day to
+func (s *Service) Statement(ctx context.Context, acctNbr string, from, to time.Time) (*entity.Statement, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	if strings.Trim(acctNbr, " ") == "" {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Account Number is required", true)
+	} else if accMap[acctNbr] == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Invalid account", true)
+	} else if from.IsZero() || to.IsZero() {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Statement period is required", true)
+	} else if to.Before(from) {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Statement period end cannot be before its start", true)
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1)
+
+	var trxs []*entity.Transaction
+	for _, trx := range trxList {
+		if trx.AccountNumber == acctNbr {
+			trxs = append(trxs, trx)
+		}
+	}
+	st := &entity.Statement{
+		AccountNumber:  acctNbr,
+		Name:           accMap[acctNbr].Name,
+		Currency:       currencyOf(accMap[acctNbr]),
+		From:           start,
+		To:             end.Add(-time.Nanosecond),
+		OpeningBalance: balanceAt(trxs, start, accMap[acctNbr].Balance),
+		ClosingBalance: balanceAt(trxs, end, accMap[acctNbr].Balance),
+		Lines:          []entity.StatementLine{},
+	}
+	for _, trx := range trxs {
+		if trx.Time.Before(start) || !trx.Time.Before(end) {
+			continue
+		}
+		line := entity.StatementLine{
+			TransactionID:  trx.ID,
+			Time:           trx.Time,
+			Type:           trx.Type,
+			Description:    describe(trx),
+			Balance:        trx.Balance,
+			ReversalStatus: trx.ReversalStatus,
+		}
+		if amount := trx.SignedAmount(); amount < 0 {
+			line.Debit = -amount
+			st.TotalDebit += -amount
+		} else {
+			line.Credit = amount
+			st.TotalCredit += amount
+		}
+		st.Lines = append(st.Lines, line)
+	}
+	return st, nil
+}
+
+// balanceAt is the balance right before t, trxs are the account's transactions in time order
+func balanceAt(trxs []*entity.Transaction, t time.Time, current float64) float64 {
+	if len(trxs) == 0 {
+		return current
+	}
+	if !trxs[0].Time.Before(t) {
+		return trxs[0].Balance - trxs[0].SignedAmount()
+	}
+	balance := trxs[0].Balance
+	for _, trx := range trxs {
+		if !trx.Time.Before(t) {
+			break
+		}
+		balance = trx.Balance
+	}
+	return balance
+}
+
+func describe(trx *entity.Transaction) string {
+	var desc string
+	switch trx.Type {
+	case entity.TransactionWithdraw:
+		desc = fmt.Sprintf("Withdraw at %s", trx.ATMID)
+	case entity.TransactionTransferOut:
+		desc = fmt.Sprintf("Transfer to %s", trx.CounterpartAccountNumber)
+		if trx.CounterpartBankCode != "" {
+			desc += " at bank " + trx.CounterpartBankCode
+		}
+	case entity.TransactionTransferIn:
+		desc = fmt.Sprintf("Transfer from %s", trx.CounterpartAccountNumber)
+		if trx.CounterpartBankCode != "" {
+			desc += " at bank " + trx.CounterpartBankCode
+		}
+	case entity.TransactionReversalIn, entity.TransactionReversalOut:
+		desc = fmt.Sprintf("Reversal of %s", trx.ReversalOf)
+	case entity.TransactionFee:
+		desc = fmt.Sprintf("Fee for %s", trx.FeeOf)
+	case entity.TransactionOverdraftInterest:
+		desc = "Overdraft interest"
+	case entity.TransactionInterest:
+		desc = "Interest"
+	case entity.TransactionBillPayment:
+		desc = fmt.Sprintf("Bill payment to %s", trx.BillerCode)
+	default:
+		desc = string(trx.Type)
+	}
+	if trx.ReferenceNumber != "" {
+		desc += fmt.Sprintf(" ref %s", trx.ReferenceNumber)
+	}
+	return desc
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatement_RunningBalanceAndTotals(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	svc.Withdraw(ctx, "112233", 30)
+	svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "112244", ToAccountNumber: "112233", Amount: 15})
+	today := time.Now()
+
+	st, resp := svc.Statement(ctx, "112233", today, today)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(100), st.OpeningBalance)
+	assert.Equal(t, float64(85), st.ClosingBalance)
+	assert.Equal(t, float64(30), st.TotalDebit)
+	assert.Equal(t, float64(15), st.TotalCredit)
+	assert.Len(t, st.Lines, 2)
+	assert.Equal(t, float64(70), st.Lines[0].Balance)
+	assert.Equal(t, float64(85), st.Lines[1].Balance)
+	assert.Equal(t, "Transfer from 112244", st.Lines[1].Description)
+}
+
+func TestStatement_PeriodBeforeTransactions(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	svc.Withdraw(ctx, "112233", 30)
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	st, resp := svc.Statement(ctx, "112233", yesterday, yesterday)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(100), st.OpeningBalance)
+	assert.Equal(t, float64(100), st.ClosingBalance)
+	assert.Empty(t, st.Lines)
+}
+
+func TestStatement_InvalidPeriod(t *testing.T) {
+	svc := New()
+	today := time.Now()
+	_, resp := svc.Statement(context.Background(), "112233", today, today.AddDate(0, 0, -1))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Statement period end cannot be before its start", resp.Message)
+}
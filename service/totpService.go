@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/envLib"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+	"github.com/fazarmitrais/atm-simulation/lib/totp"
+)
+
+const (
+	// defaultStepUpThreshold is the amount above which transfers and withdrawals need a one-time password
+	// when STEP_UP_THRESHOLD is not set
+	defaultStepUpThreshold = 500
+	// maxOTPFailures wrong one-time passwords in a row lock the step-up for otpLockout, so codes cannot be guessed
+	maxOTPFailures = 5
+	otpLockout     = 15 * time.Minute
+)
+
+type otpContextKey struct{}
+
+type preAuthorizedContextKey struct{}
+
+// WithOTP passes the one-time password the customer entered to the operations that need a step-up
+func WithOTP(ctx context.Context, otp string) context.Context {
+	return context.WithValue(ctx, otpContextKey{}, otp)
+}
+
+func otpOf(ctx context.Context) string {
+	otp, _ := ctx.Value(otpContextKey{}).(string)
+	return otp
+}
+
+// preAuthorized runs an operation the customer authorised earlier without a step-up, like the payment
+// of a standing order or the redemption of a cardless withdrawal
+func preAuthorized(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preAuthorizedContextKey{}, true)
+}
+
+func stepUpThreshold() float64 {
+	if value := envLib.GetEnv("STEP_UP_THRESHOLD"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err == nil && threshold >= 0 {
+			return threshold
+		}
+		log.Printf("Invalid STEP_UP_THRESHOLD %q, using %d \n", value, defaultStepUpThreshold)
+	}
+	return defaultStepUpThreshold
+}
+
+// newDestination reports whether acctNbr never transferred to toAcctNbr at bankCode before
+func newDestination(acctNbr, bankCode, toAcctNbr string) bool {
+	if bankCode == "" {
+		bankCode = BankCode()
+	}
+	for _, trx := range trxList {
+		if trx.AccountNumber != acctNbr || trx.Type != entity.TransactionTransferOut || trx.CounterpartAccountNumber != toAcctNbr {
+			continue
+		} else if trx.CounterpartBankCode == bankCode || (trx.CounterpartBankCode == "" && bankCode == BankCode()) {
+			return false
+		}
+	}
+	return true
+}
+
+// aboveStepUpThreshold tells whether amount, in the currency of acc, is above the threshold in the base currency
+func (s *Service) aboveStepUpThreshold(acc *entity.Account, amount float64) bool {
+	return s.toBase(acc, amount) > stepUpThreshold()
+}
+
+// transferStepUp tells whether a transfer of from needs a one-time password : above the threshold, or to a destination
+// the account never transferred to
+func (s *Service) transferStepUp(from *entity.Account, bankCode, toAcctNbr string, amount float64) bool {
+	return s.aboveStepUpThreshold(from, amount) || newDestination(from.AccountNumber, bankCode, toAcctNbr)
+}
+
+// checkStepUp asks for the one-time password of ctx when the operation needs it and acc has enabled TOTP.
+// The code is used up even if the operation fails afterwards, so it is saved right away : a code cannot be
+// replayed after a restart.
+func (s *Service) checkStepUp(ctx context.Context, acc *entity.Account, needed bool) *responseFormatter.ResponseFormatter {
+	if !needed || acc.TOTP == nil || !acc.TOTP.Enabled {
+		return nil
+	} else if done, _ := ctx.Value(preAuthorizedContextKey{}).(bool); done {
+		return nil
+	}
+	resp := s.verifyOTP(acc, otpOf(ctx))
+	s.save()
+	return resp
+}
+
+func (s *Service) verifyOTP(acc *entity.Account, otp string) *responseFormatter.ResponseFormatter {
+	f := acc.TOTP
+	now := s.clock.Now()
+	if otp == "" {
+		return responseFormatter.New(http.StatusUnauthorized, "One-time password is required for this operation", true).
+			WithCode(ErrCodeStepUpRequired)
+	} else if f.LockedUntil != nil && now.Before(*f.LockedUntil) {
+		return responseFormatter.New(http.StatusForbidden, "Too many invalid one-time passwords, please try again later", true).
+			WithCode(ErrCodeOTPLocked)
+	}
+	counter, ok := totp.Verify(f.Secret, otp, now, f.LastCounter)
+	if !ok {
+		f.Failures++
+		if f.Failures >= maxOTPFailures {
+			lockedUntil := now.Add(otpLockout)
+			f.LockedUntil = &lockedUntil
+			f.Failures = 0
+		}
+		return responseFormatter.New(http.StatusUnauthorized, "Invalid one-time password", true).WithCode(ErrCodeInvalidOTP)
+	}
+	f.LastCounter = counter
+	f.Failures = 0
+	f.LockedUntil = nil
+	return nil
+}
+
+// EnrolTOTP gives acctNbr a new TOTP secret to add to an authenticator app. The second factor is only
+// enabled once ConfirmTOTP gets a first code, enrolling again before that replaces the secret.
+func (s *Service) EnrolTOTP(ctx context.Context, acctNbr string) (*entity.TOTPEnrolment, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.TOTP != nil && acc.TOTP.Enabled {
+		return nil, responseFormatter.New(http.StatusConflict, "One-time passwords are already enabled", true)
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, responseFormatter.New(http.StatusInternalServerError, "Failed generating secret : "+err.Error(), true)
+	}
+	acc.TOTP = &entity.TOTP{Secret: secret}
+	s.save()
+	return &entity.TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(BankName(), acctNbr, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables the second factor enrolled by EnrolTOTP, with a code of the authenticator app
+func (s *Service) ConfirmTOTP(ctx context.Context, acctNbr, otp string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.TOTP == nil {
+		return nil, responseFormatter.New(http.StatusBadRequest, "Enrol an authenticator app first", true)
+	} else if acc.TOTP.Enabled {
+		return nil, responseFormatter.New(http.StatusConflict, "One-time passwords are already enabled", true)
+	} else if resp := s.verifyOTP(acc, otp); resp != nil {
+		s.save()
+		return nil, resp
+	}
+	now := s.clock.Now()
+	acc.TOTP.Enabled = true
+	acc.TOTP.EnabledAt = &now
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+// DisableTOTP removes the second factor of acctNbr, it takes a one-time password in ctx
+func (s *Service) DisableTOTP(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	} else if acc.TOTP == nil || !acc.TOTP.Enabled {
+		return nil, responseFormatter.New(http.StatusConflict, "One-time passwords are not enabled", true)
+	} else if resp := s.checkStepUp(ctx, acc, true); resp != nil {
+		return nil, resp
+	}
+	acc.TOTP = nil
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
+
+// ResetTOTP removes the second factor of an account without a code, for a customer who lost the authenticator app
+func (s *Service) ResetTOTP(ctx context.Context, acctNbr string) (*entity.AccountResponse, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	acc, resp := findAccount(acctNbr)
+	if resp != nil {
+		return nil, resp
+	}
+	acc.TOTP = nil
+	s.save()
+	return s.toAccountResponse(acc), nil
+}
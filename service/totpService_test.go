@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/clock"
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/fx"
+	"github.com/fazarmitrais/atm-simulation/lib/totp"
+	"github.com/fazarmitrais/atm-simulation/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTOTPService opens 445566 with a balance of 2000 and enables TOTP on it, the returned func is the code of the
+// next period so every call gives a code not used yet
+func newTOTPService(t *testing.T, opts ...Option) (*Service, *clock.Fake, func() string) {
+	c := clock.NewFake(monday)
+	svc := New(append([]Option{WithClock(c)}, opts...)...)
+	ctx := context.Background()
+	svc.CreateAccount(ctx, entity.Account{Name: "Ann", AccountNumber: "445566", PIN: "445566", Balance: 2000})
+
+	enrolment, resp := svc.EnrolTOTP(ctx, "445566")
+	assert.Nil(t, resp)
+	assert.Contains(t, enrolment.ProvisioningURI, "otpauth://totp/")
+	assert.Contains(t, enrolment.ProvisioningURI, "secret="+enrolment.Secret)
+	next := func() string {
+		c.Advance(totp.Period)
+		code, _ := totp.Code(enrolment.Secret, totp.Counter(c.Now()))
+		return code
+	}
+	acc, resp := svc.ConfirmTOTP(ctx, "445566", next())
+	assert.Nil(t, resp)
+	assert.True(t, acc.TOTPEnabled)
+	return svc, c, next
+}
+
+func TestConfirmTOTP(t *testing.T) {
+	svc := New(WithClock(clock.NewFake(monday)))
+	ctx := context.Background()
+	_, resp := svc.ConfirmTOTP(ctx, "112233", "123456")
+	assert.Equal(t, "Enrol an authenticator app first", resp.Message)
+	svc.EnrolTOTP(ctx, "112233")
+	_, resp = svc.ConfirmTOTP(ctx, "112233", "")
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+
+	// a pending enrolment is not enforced
+	_, resp = svc.Withdraw(ctx, "112233", 10)
+	assert.Nil(t, resp)
+}
+
+func TestStepUp_AboveThreshold(t *testing.T) {
+	svc, _, next := newTOTPService(t)
+	ctx := context.Background()
+	_, resp := svc.Withdraw(ctx, "445566", 600)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+	_, resp = svc.Withdraw(WithOTP(ctx, "000000"), "445566", 600)
+	assert.Equal(t, ErrCodeInvalidOTP, resp.Code)
+
+	acc, resp := svc.Withdraw(WithOTP(ctx, next()), "445566", 600)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(1400), acc.Balance)
+	_, resp = svc.Withdraw(ctx, "445566", 500)
+	assert.Nil(t, resp, "the threshold itself needs no step-up")
+
+	_, resp = svc.CreateCardlessWithdrawal(ctx, "445566", entity.CardlessWithdrawalRequest{Amount: 600, PIN: "4321"})
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+}
+
+func TestStepUp_ThresholdInAccountCurrency(t *testing.T) {
+	provider, _ := fx.New(fx.Rate{Currency: "JPY", Rate: 150})
+	c := clock.NewFake(monday)
+	svc := New(WithClock(c), WithFX(provider))
+	ctx := context.Background()
+	svc.CreateAccount(ctx, entity.Account{Name: "Ann", AccountNumber: "778899", PIN: "778899", Balance: 300000, Currency: "JPY"})
+	enrolment, _ := svc.EnrolTOTP(ctx, "778899")
+	next := func() string {
+		c.Advance(totp.Period)
+		code, _ := totp.Code(enrolment.Secret, totp.Counter(c.Now()))
+		return code
+	}
+	_, resp := svc.ConfirmTOTP(ctx, "778899", next())
+	assert.Nil(t, resp)
+	transfer := entity.Transfer{FromAccountNumber: "778899", ToAccountNumber: "112233", Amount: 1500}
+	_, resp = svc.Transfer(WithOTP(ctx, next()), transfer)
+	assert.Nil(t, resp)
+
+	transfer.Amount = 60000
+	_, resp = svc.Transfer(ctx, transfer)
+	assert.Nil(t, resp, "JPY 60000 is $400, below the threshold")
+	transfer.Amount = 90000
+	_, resp = svc.Transfer(ctx, transfer)
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code, "JPY 90000 is $600, above the threshold")
+}
+
+func TestStepUp_CodeUsedOnceAcrossRestart(t *testing.T) {
+	repo := repository.NewMemory()
+	svc, c, next := newTOTPService(t, WithRepository(repo))
+	ctx := context.Background()
+	svc.RegisterATM(ctx, entity.ATM{ID: "ATM009", Cash: 100})
+	otp := next()
+	_, resp := svc.Withdraw(WithOTP(WithATM(ctx, "ATM009"), otp), "445566", 600)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	svc = New(WithClock(c), WithRepository(repo))
+	_, resp = svc.Withdraw(WithOTP(ctx, otp), "445566", 600)
+	if assert.NotNil(t, resp, "the code was used before the restart") {
+		assert.Equal(t, ErrCodeInvalidOTP, resp.Code)
+	}
+}
+
+func TestStepUp_NewDestination(t *testing.T) {
+	svc, _, next := newTOTPService(t)
+	ctx := context.Background()
+	_, resp := svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112244", Amount: 10})
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+	_, resp = svc.Transfer(WithOTP(ctx, next()), entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112244", Amount: 10})
+	assert.Nil(t, resp)
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112244", Amount: 10})
+	assert.Nil(t, resp, "the destination is known now")
+	_, resp = svc.Transfer(ctx, entity.Transfer{FromAccountNumber: "445566", ToAccountNumber: "112244", Amount: 600})
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+
+	_, resp = svc.CreateStandingOrder(ctx, "445566", entity.StandingOrder{ToAccountNumber: "112244", Amount: 600, Day: 1})
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+}
+
+func TestStepUp_CodeUsedOnce(t *testing.T) {
+	svc, _, next := newTOTPService(t)
+	ctx := WithOTP(context.Background(), next())
+	_, resp := svc.Withdraw(ctx, "445566", 600)
+	assert.Nil(t, resp)
+	_, resp = svc.Withdraw(ctx, "445566", 600)
+	assert.Equal(t, ErrCodeInvalidOTP, resp.Code)
+}
+
+func TestStepUp_LockedAfterFailures(t *testing.T) {
+	svc, c, next := newTOTPService(t)
+	ctx := context.Background()
+	for i := 0; i < maxOTPFailures; i++ {
+		_, resp := svc.Withdraw(WithOTP(ctx, "000000"), "445566", 600)
+		assert.Equal(t, ErrCodeInvalidOTP, resp.Code)
+	}
+	_, resp := svc.Withdraw(WithOTP(ctx, next()), "445566", 600)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, ErrCodeOTPLocked, resp.Code)
+
+	c.Advance(otpLockout)
+	_, resp = svc.Withdraw(WithOTP(ctx, next()), "445566", 600)
+	assert.Nil(t, resp)
+}
+
+func TestStepUp_PreAuthorizedOperations(t *testing.T) {
+	svc, _, next := newTOTPService(t)
+	ctx := context.Background()
+	w, resp := svc.CreateCardlessWithdrawal(WithOTP(ctx, next()), "445566", entity.CardlessWithdrawalRequest{Amount: 600, PIN: "4321"})
+	assert.Nil(t, resp)
+	_, resp = svc.RedeemCardlessWithdrawal(ctx, entity.CardlessRedemption{Code: w.Code, PIN: "4321"})
+	assert.Nil(t, resp, "the cash is collected without the authenticator app")
+}
+
+func TestDisableTOTP(t *testing.T) {
+	svc, _, next := newTOTPService(t)
+	ctx := context.Background()
+	_, resp := svc.DisableTOTP(ctx, "445566")
+	assert.Equal(t, ErrCodeStepUpRequired, resp.Code)
+	acc, resp := svc.DisableTOTP(WithOTP(ctx, next()), "445566")
+	assert.Nil(t, resp)
+	assert.False(t, acc.TOTPEnabled)
+	_, resp = svc.Withdraw(ctx, "445566", 600)
+	assert.Nil(t, resp)
+}
+
+func TestResetTOTP(t *testing.T) {
+	svc, _, _ := newTOTPService(t)
+	acc, resp := svc.ResetTOTP(context.Background(), "445566")
+	assert.Nil(t, resp)
+	assert.False(t, acc.TOTPEnabled)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/fazarmitrais/atm-simulation/lib/responseFormatter"
+)
+
+var (
+	trxList  []*entity.Transaction
+	trxMap   = make(map[string]*entity.Transaction)
+	trxSeq   int
+	receipts = make(map[string]*entity.Receipt)
+)
+
+func initTransaction() {
+	trxList = nil
+	trxMap = make(map[string]*entity.Transaction)
+	trxSeq = 0
+	receipts = make(map[string]*entity.Receipt)
+}
+
+// nextTransactionID reserves the ID of a transaction before it is recorded,
+// so that its journal entry can refer to it
+func nextTransactionID() string {
+	trxSeq++
+	return entity.FormatTransactionID(trxSeq)
+}
+
+// recordTransaction stores trx with the account's balance after the change
+func (s *Service) recordTransaction(ctx context.Context, trx entity.Transaction) *entity.Transaction {
+	if trx.ID == "" {
+		trx.ID = nextTransactionID()
+	}
+	if trx.ATMID == "" {
+		trx.ATMID = atmID(ctx)
+	}
+	if trx.Currency == "" {
+		trx.Currency = currencyOf(accMap[trx.AccountNumber])
+	}
+	trx.Balance = accMap[trx.AccountNumber].Balance
+	trx.Time = s.clock.Now()
+	trxList = append(trxList, &trx)
+	trxMap[trx.ID] = &trx
+	return &trx
+}
+
+// IssueReceipt prints the receipt of a transaction, customers who choose not to have one never call it
+func (s *Service) IssueReceipt(ctx context.Context, trxID string) (*entity.Receipt, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	trx := trxMap[trxID]
+	if trx == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "Transaction not found", true)
+	}
+	receipt := &entity.Receipt{
+		TransactionID: trx.ID,
+		ATMID:         trx.ATMID,
+		AccountNumber: maskAccountNumber(trx.AccountNumber),
+		Time:          trx.Time,
+		Type:          trx.Type,
+		Amount:        trx.Amount,
+		Currency:      trx.Currency,
+		Reference:     trx.ReferenceNumber,
+		Balance:       trx.Balance,
+	}
+	if trx.Type == entity.TransactionTransferOut {
+		receipt.DestinationAccountNumber = trx.CounterpartAccountNumber
+		if trx.FXRate != 0 {
+			receipt.ConvertedAmount = trx.CounterpartAmount
+			receipt.ConvertedCurrency = currencyOf(accMap[trx.CounterpartAccountNumber])
+			receipt.FXRate = trx.FXRate
+		}
+		if trx.CounterpartBankCode != "" {
+			receipt.DestinationBank = trx.CounterpartBankCode
+			if b, ok := s.network.Bank(trx.CounterpartBankCode); ok {
+				receipt.DestinationBank = b.Name()
+			}
+		}
+	}
+	if trx.Type == entity.TransactionBillPayment {
+		receipt.Biller = trx.BillerCode
+		if b, ok := s.billers.Biller(trx.BillerCode); ok {
+			receipt.Biller = b.Name
+		}
+		receipt.BillerReference = trx.BillerReference
+	}
+	if receipt.Reference == "" {
+		receipt.Reference = trx.ID
+	}
+	if feeTrx := feeOf(trx); feeTrx != nil {
+		receipt.Fee = feeTrx.Amount
+		receipt.Balance = feeTrx.Balance
+	}
+	receipts[trx.ID] = receipt
+	return receipt, nil
+}
+
+func (s *Service) Receipt(ctx context.Context, acctNbr, trxID string) (*entity.Receipt, *responseFormatter.ResponseFormatter) {
+	ctx, unlock := s.lock(ctx)
+	defer unlock()
+	trx := trxMap[trxID]
+	if trx == nil || trx.AccountNumber != acctNbr {
+		return nil, responseFormatter.New(http.StatusNotFound, "Transaction not found", true)
+	} else if receipts[trxID] == nil {
+		return nil, responseFormatter.New(http.StatusNotFound, "No receipt was printed for this transaction", true)
+	}
+	return receipts[trxID], nil
+}
+
+// maskAccountNumber only keeps the last 2 digits visible
+func maskAccountNumber(acctNbr string) string {
+	if len(acctNbr) <= 2 {
+		return acctNbr
+	}
+	masked := []byte(acctNbr)
+	for i := 0; i < len(masked)-2; i++ {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
+
+// feeOf finds the FEE transaction charged for trx, nil when it had no fee
+func feeOf(trx *entity.Transaction) *entity.Transaction {
+	for i := len(trxList) - 1; i >= 0; i-- {
+		if trxList[i].FeeOf == trx.ID {
+			return trxList[i]
+		}
+	}
+	return nil
+}
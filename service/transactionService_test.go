@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fazarmitrais/atm-simulation/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueReceipt_Withdraw(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 30)
+	receipt, resp := svc.IssueReceipt(ctx, acc.TransactionID)
+	assert.Nil(t, resp)
+	assert.Equal(t, "****33", receipt.AccountNumber)
+	assert.Equal(t, entity.TransactionWithdraw, receipt.Type)
+	assert.Equal(t, float64(30), receipt.Amount)
+	assert.Equal(t, float64(70), receipt.Balance)
+	assert.Equal(t, acc.TransactionID, receipt.Reference)
+
+	got, resp := svc.Receipt(ctx, "112233", acc.TransactionID)
+	assert.Nil(t, resp)
+	assert.Equal(t, receipt, got)
+}
+
+func TestIssueReceipt_Transfer(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Transfer(ctx, entity.Transfer{
+		FromAccountNumber: "112233",
+		ToAccountNumber:   "112244",
+		Amount:            20,
+		ReferenceNumber:   "213342",
+	})
+	receipt, resp := svc.IssueReceipt(ctx, acc.TransactionID)
+	assert.Nil(t, resp)
+	assert.Equal(t, entity.TransactionTransferOut, receipt.Type)
+	assert.Equal(t, "112244", receipt.DestinationAccountNumber)
+	assert.Equal(t, "213342", receipt.Reference)
+	assert.Equal(t, float64(80), receipt.Balance)
+}
+
+func TestReceipt_NoReceipt(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 30)
+	_, resp := svc.Receipt(ctx, "112233", acc.TransactionID)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "No receipt was printed for this transaction", resp.Message)
+}
+
+func TestReceipt_OtherAccount(t *testing.T) {
+	svc := New()
+	ctx := context.Background()
+	acc, _ := svc.Withdraw(ctx, "112233", 30)
+	svc.IssueReceipt(ctx, acc.TransactionID)
+	_, resp := svc.Receipt(ctx, "112244", acc.TransactionID)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}